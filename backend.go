@@ -0,0 +1,121 @@
+package slipscheme
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Backend overrides where generated files end up. If not set,
+// NewSchemaProcessor picks a FileOutputBackend (rooted at OutputDir) or a
+// StdoutOutputBackend, depending on Stdout.
+func Backend(backend OutputBackend) SchemaProcessorOption {
+	return func(s *SchemaProcessor) {
+		s.backend = backend
+	}
+}
+
+// Formatter overrides how generated files are formatted before being
+// handed to the OutputBackend. It's only consulted when Format(true) is
+// set (the slipscheme command's -fmt flag sets it by default). This lets
+// callers plug in goimports instead of the
+// default go/format.Source.
+func Formatter(formatter func([]byte) ([]byte, error)) SchemaProcessorOption {
+	return func(s *SchemaProcessor) {
+		s.formatter = formatter
+	}
+}
+
+// OutputBackend is where slipscheme delivers generated Go source. The
+// default is FileOutputBackend (or StdoutOutputBackend when Stdout(true)
+// is set); pass a Backend() option to plug in something else, e.g. a
+// ManifestOutputBackend for build-system integration.
+type OutputBackend interface {
+	WriteFile(path string, contents []byte) error
+	Finalize() error
+}
+
+// FileOutputBackend writes generated files to disk under Dir, skipping
+// files that already exist unless Overwrite is set.
+type FileOutputBackend struct {
+	Dir       string
+	Overwrite bool
+}
+
+func (b *FileOutputBackend) WriteFile(path string, contents []byte) error {
+	file := filepath.Join(b.Dir, path)
+	if !b.Overwrite {
+		if _, err := os.Stat(file); err == nil {
+			log.Printf("File %s already exists, skipping without -overwrite", file)
+			return nil
+		}
+	}
+	fmt.Printf("Writing %s\n", file)
+	return os.WriteFile(file, contents, 0o644)
+}
+
+func (b *FileOutputBackend) Finalize() error {
+	return nil
+}
+
+// StdoutOutputBackend writes each generated file's contents to Out, one
+// after another, ignoring path.
+type StdoutOutputBackend struct {
+	Out io.Writer
+}
+
+func (b *StdoutOutputBackend) WriteFile(path string, contents []byte) error {
+	_, err := b.Out.Write(contents)
+	return err
+}
+
+func (b *StdoutOutputBackend) Finalize() error {
+	return nil
+}
+
+// ManifestOutputBackend collects generated files in memory instead of
+// writing them to disk, so callers such as a Bazel genrule or other
+// build-system integration can write them out (or feed them elsewhere)
+// under their own control.
+type ManifestOutputBackend struct {
+	Files map[string][]byte
+}
+
+func (b *ManifestOutputBackend) WriteFile(path string, contents []byte) error {
+	if b.Files == nil {
+		b.Files = map[string][]byte{}
+	}
+	b.Files[path] = contents
+	return nil
+}
+
+func (b *ManifestOutputBackend) Finalize() error {
+	return nil
+}
+
+// importBlock renders a deduplicated import statement for the given
+// import paths, or an empty string if there's nothing to import.
+func importBlock(imports []string) string {
+	if len(imports) == 0 {
+		return ""
+	}
+	seen := map[string]bool{}
+	unique := []string{}
+	for _, imp := range imports {
+		if !seen[imp] {
+			seen[imp] = true
+			unique = append(unique, imp)
+		}
+	}
+	sort.Strings(unique)
+
+	block := "\nimport (\n"
+	for _, imp := range unique {
+		block += fmt.Sprintf("    %q\n", imp)
+	}
+	block += ")\n\n"
+	return block
+}
@@ -58,6 +58,18 @@ func (r *Replacements) String() string {
 	return "[" + strings.TrimSpace(buf.String()) + "]"
 }
 
+func parseInputFormat(s string) (slipscheme.InputFormat, error) {
+	switch s {
+	case "auto":
+		return slipscheme.AutoDetect, nil
+	case "json-schema":
+		return slipscheme.JSONSchemaInput, nil
+	case "openapi":
+		return slipscheme.OpenAPIInput, nil
+	}
+	return 0, fmt.Errorf("unknown -input-format %q (want auto, json-schema, or openapi)", s)
+}
+
 func runMain(arguments []string, io slipscheme.Stdio) int {
 	flags := flag.NewFlagSet(arguments[0], flag.ExitOnError)
 	outputDir := flags.String("dir", ".", "output directory for go files")
@@ -66,6 +78,9 @@ func runMain(arguments []string, io slipscheme.Stdio) int {
 	stdout := flags.Bool("stdout", false, "print go code to stdout rather than files")
 	format := flags.Bool("fmt", true, "pass code through gofmt")
 	comments := flags.Bool("comments", true, "enable/disable print comments")
+	validate := flags.Bool("validate", false, "generate Validate() methods enforcing schema validation keywords")
+	markdown := flags.Bool("markdown", false, "generate companion Markdown reference docs alongside the generated go files")
+	inputFormat := flags.String("input-format", "auto", "input format: auto, json-schema, or openapi")
 
 	replacements := Replacements{}
 	flags.Var(&replacements, "replacements", "comma-separated values to replace")
@@ -73,6 +88,12 @@ func runMain(arguments []string, io slipscheme.Stdio) int {
 	flags.SetOutput(io.Stderr)
 	flags.Parse(arguments[1:])
 
+	inputFmt, err := parseInputFormat(*inputFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 1
+	}
+
 	processor := slipscheme.NewSchemaProcessor(
 		slipscheme.OutputDir(*outputDir),
 		slipscheme.PackageName(*pkgName),
@@ -80,6 +101,9 @@ func runMain(arguments []string, io slipscheme.Stdio) int {
 		slipscheme.Stdout(*stdout),
 		slipscheme.Format(*format),
 		slipscheme.Comment(*comments),
+		slipscheme.WithValidation(*validate),
+		slipscheme.Markdown(*markdown),
+		slipscheme.WithInputFormat(inputFmt),
 		slipscheme.IO(io),
 		slipscheme.Replacements(replacements),
 	)
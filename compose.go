@@ -0,0 +1,468 @@
+package slipscheme
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+)
+
+func (s *SchemaProcessor) mergeSchemas(parent *Schema, schemas ...*Schema) (typeName string, err error) {
+	switch len(schemas) {
+	case 0:
+		return "", fmt.Errorf("merging zero schemas")
+	case 1:
+		// TODO: Not sure this is correct, should the name come from the oneOf
+		// schema or the only constraint schema?
+		return s.processSchema(schemas[0])
+	}
+
+	// A member pointed at a named #/definitions or #/$defs entry arrives
+	// here as a bare {Ref: ...} shell - setRoot resolves those lazily so
+	// resolveRef can generate the shared named type once instead of
+	// inlining it everywhere. Swap in the actual definition so the
+	// common/uncommon property split below sees its real Properties
+	// instead of an empty struct, the same way processAllOf resolves its
+	// own $ref members before reading them.
+	resolved := make([]*Schema, len(schemas))
+	for i, schema := range schemas {
+		if schema.Ref != "" && isNamedDefRef(schema.Ref) {
+			target, err := lookupRef(parent.Root, schema.Ref)
+			if err != nil {
+				return "", err
+			}
+			resolved[i] = target
+			continue
+		}
+		resolved[i] = schema
+	}
+	schemas = resolved
+
+	mergedParent := &Schema{
+		Description: parent.Name(),
+		Root:        parent.Root,
+		Properties:  map[string]*Schema{},
+		Type:        OBJECT,
+	}
+
+	uncommonSchemas := map[string]*Schema{}
+	for _, schema := range schemas {
+		// TODO we need a Schema.Copy() function
+		uncommonSchemas[schema.Name()] = &Schema{
+			Description: schema.Name(),
+			Root:        parent.Root,
+			Properties:  map[string]*Schema{},
+			Type:        schema.Type,
+		}
+	}
+
+	// find any common properties, and assign them to mergeParent
+	// else create subtype with uncommon properties with `json:",inline"`
+
+	allProperties := map[string]int{}
+	for _, schema := range schemas {
+		for p := range schema.Properties {
+			allProperties[p]++
+		}
+	}
+
+	for _, schema := range schemas {
+		for p, v := range schema.Properties {
+			if allProperties[p] > 1 {
+				mergedParent.Properties[p] = v
+			} else {
+				uncommonSchemas[schema.Name()].Properties[p] = v
+			}
+		}
+	}
+
+	typeName = s.toCamel(mergedParent.Name())
+	typeData := fmt.Sprintf("%stype %s struct {\n", s.structComment(mergedParent, typeName), typeName)
+
+	keys := []string{}
+	for k := range mergedParent.Properties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := mergedParent.Properties[k]
+		subTypeName, err := s.processSchema(v)
+		if err != nil {
+			return "", err
+		}
+		typeData += fmt.Sprintf("    %s %s `json:\"%s,omitempty\" yaml:\"%s,omitempty\"`\n", s.toCamel(k), subTypeName, k, k)
+	}
+
+	oneOfKeys := []string{}
+	for name, schema := range uncommonSchemas {
+		if len(schema.Properties) > 0 {
+			oneOfKeys = append(oneOfKeys, name)
+		}
+	}
+	sort.Strings(oneOfKeys)
+
+	for _, k := range oneOfKeys {
+		oneOfTypeName, err := s.processSchema(uncommonSchemas[k])
+		if err != nil {
+			return "", err
+		}
+		typeData += fmt.Sprintf("    %s %s `json:\",inline\" yaml:\",inline\"`\n", s.toCamel(k), oneOfTypeName)
+	}
+
+	typeData += "}\n\n"
+	if err := s.writeGoCode(typeName, typeData); err != nil {
+		return "", err
+	}
+	return typeName, nil
+}
+
+// isNamedDefRef reports whether ref points directly at an entry of
+// #/definitions or #/$defs, e.g. "#/definitions/Foo" or "#/$defs/Foo".
+// Those refs are resolved to a shared named type by resolveRef instead
+// of being inlined by setRoot.
+func isNamedDefRef(ref string) bool {
+	parts := strings.Split(ref, "/")
+	return len(parts) == 3 && parts[0] == "#" && (parts[1] == "definitions" || parts[1] == "$defs")
+}
+
+// lookupRef resolves a "#/definitions/Foo" or "#/$defs/Foo" ref against
+// root, returning the referenced Schema.
+func lookupRef(root *Schema, ref string) (*Schema, error) {
+	parts := strings.Split(ref, "/")
+	name := parts[2]
+	switch parts[1] {
+	case "definitions":
+		if target, ok := root.Definitions[name]; ok {
+			return target, nil
+		}
+	case "$defs":
+		if target, ok := root.Defs[name]; ok {
+			return target, nil
+		}
+	}
+	return nil, fmt.Errorf("$ref %q not found", ref)
+}
+
+// resolveRef generates (once) the named type for a #/definitions or
+// #/$defs entry and returns its Go type expression, reusing the same
+// type expression for every subsequent reference to ref. The type is
+// reserved in refTypes before recursing so self-referential
+// definitions terminate instead of recursing forever.
+func (s *SchemaProcessor) resolveRef(schema *Schema) (string, error) {
+	if typeExpr, ok := s.refTypes[schema.Ref]; ok {
+		return typeExpr, nil
+	}
+
+	target, err := lookupRef(schema.Root, schema.Ref)
+	if err != nil {
+		return "", err
+	}
+
+	if s.refTypes == nil {
+		s.refTypes = map[string]string{}
+	}
+	// Reserve a placeholder before recursing into target, not just for
+	// the Properties-bearing struct case: a definition can equally
+	// self-reference through PatternProperties (e.g. a map-of-self Tree
+	// schema), and without a reservation in
+	// place for every OBJECT shape, resolveRef recurses into
+	// processSchema into resolveRef on the same $ref with nothing to
+	// short-circuit it, overflowing the stack.
+	if target.Type == OBJECT {
+		s.refTypes[schema.Ref] = fmt.Sprintf("*%s", s.toCamel(target.Name()))
+	}
+
+	typeExpr, err := s.processSchema(target)
+	if err != nil {
+		return "", err
+	}
+	s.refTypes[schema.Ref] = typeExpr
+	return typeExpr, nil
+}
+
+// processAllOf merges the properties of every allOf member into a
+// single struct. Named ($ref) members are embedded as anonymous fields
+// for Go-idiomatic composition; inline sub-schemas have their
+// properties merged directly into the enclosing struct. When
+// WithValidation is enabled, the composed type's Validate() also calls
+// each embedded member's own Validate(), so requirements declared on the
+// referenced schema are still enforced.
+func (s *SchemaProcessor) processAllOf(schema *Schema) (string, error) {
+	typeName := s.toCamel(schema.Name())
+	typeData := fmt.Sprintf("%stype %s struct {\n", s.structComment(schema, typeName), typeName)
+
+	required := map[string]bool{}
+	for _, r := range schema.Required {
+		required[r] = true
+	}
+
+	merged := map[string]*Schema{}
+	for k, v := range schema.Properties {
+		merged[k] = v
+	}
+
+	// embedded collects the field names of anonymously-embedded named
+	// $ref members, so the composed type's Validate() (below) can also
+	// run each embedded member's own Validate(). mdEmbeds mirrors it as
+	// Markdown links, so the property table's caller (markdownAllOf) can
+	// point readers at the embedded member's own field list instead of
+	// silently omitting fields that are real, embedded JSON fields on
+	// this type.
+	var embedded []string
+	var mdEmbeds []string
+	for _, sub := range schema.AllOf {
+		if sub.Ref != "" && isNamedDefRef(sub.Ref) {
+			typeExpr, err := s.resolveRef(sub)
+			if err != nil {
+				return "", err
+			}
+			typeData += fmt.Sprintf("    %s\n", typeExpr)
+			bare := strings.TrimPrefix(typeExpr, "*")
+			embedded = append(embedded, bare)
+			mdEmbeds = append(mdEmbeds, fmt.Sprintf("[`%s`](#%s)", bare, markdownAnchor(bare)))
+			continue
+		}
+		for k, v := range sub.Properties {
+			merged[k] = v
+		}
+		for _, r := range sub.Required {
+			required[r] = true
+		}
+	}
+
+	keys := []string{}
+	for k := range merged {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var checks []string
+	var imports []string
+	var mdRows []string
+	for _, k := range keys {
+		v := merged[k]
+		subTypeName, err := s.processSchema(v)
+		if err != nil {
+			return "", err
+		}
+		wrappedTypeName := nullableType(subTypeName, v)
+		nullable := wrappedTypeName != subTypeName
+		subTypeName = wrappedTypeName
+		fieldName := s.toCamel(k)
+		if v.XGoName != "" {
+			fieldName = s.toCamel(v.XGoName)
+		}
+		typeData += fmt.Sprintf("    %s %s `json:\"%s,omitempty\" yaml:\"%s,omitempty\"`%s\n", fieldName, subTypeName, k, k, fieldVisibilityComment(v))
+		if s.validate {
+			fieldChecks, fieldImports := s.validationChecks(fieldName, k, v, required[k], nullable)
+			checks = append(checks, fieldChecks...)
+			imports = append(imports, fieldImports...)
+			if nested := nestedValidateCheck(fieldName, k, subTypeName, s.validatedTypes, s.arrayElemTypes, s.mapValueTypes); nested != "" {
+				checks = append(checks, nested)
+			}
+		}
+		if s.markdown {
+			mdRows = append(mdRows, markdownRow(k, v, subTypeName, required[k]))
+		}
+	}
+	typeData += "}\n\n"
+
+	if s.validate {
+		typeData += fmt.Sprintf("func (t *%s) Validate() error {\n", typeName)
+		for _, name := range embedded {
+			typeData += fmt.Sprintf("    if t.%s != nil {\n        if err := t.%s.Validate(); err != nil {\n            return err\n        }\n    }\n", name, name)
+		}
+		for _, check := range checks {
+			typeData += check
+		}
+		typeData += "    return nil\n}\n\n"
+		if len(checks) > 0 {
+			imports = append(imports, "fmt")
+		}
+		if s.validatedTypes == nil {
+			s.validatedTypes = map[string]bool{}
+		}
+		s.validatedTypes[typeName] = true
+	}
+
+	if err := s.writeGoCode(typeName, typeData, imports...); err != nil {
+		return "", err
+	}
+
+	if s.markdown {
+		if err := s.writeMarkdown(typeName, markdownAllOf(schema, typeName, mdEmbeds, mdRows)); err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("*%s", typeName), nil
+}
+
+// processAnyOf generates a wrapper struct with one pointer field per
+// anyOf variant, along with an UnmarshalJSON that populates whichever
+// variants successfully unmarshal - and, for a variant whose own
+// Validate() was generated (WithValidation), also pass it. That still
+// isn't exclusive dispatch: json.Unmarshal ignores unknown fields, so
+// when variants share or entirely lack required properties, more than
+// one field can end up non-nil for the same payload. The generated
+// type's warning comment (below) calls this out so callers know to check
+// a discriminating field explicitly rather than trust which pointer is
+// set.
+func (s *SchemaProcessor) processAnyOf(schema *Schema) (string, error) {
+	typeName := s.toCamel(schema.Name())
+	warning := fmt.Sprintf("// %s is populated by unmarshaling the payload into every anyOf variant\n// that accepts it (and, if validated, passes its own Validate()). When\n// variants share or lack required fields, more than one field may end up\n// non-nil for the same input - check a discriminating field explicitly\n// rather than assuming exclusivity. MarshalJSON re-emits whichever\n// variant was populated first, in declaration order.\n", typeName)
+	typeData := warning + fmt.Sprintf("%stype %s struct {\n", s.structComment(schema, typeName), typeName)
+
+	type variant struct {
+		fieldName string
+		typeName  string
+	}
+	var variants []variant
+	var mdVariants []string
+	for _, sub := range schema.AnyOf {
+		subTypeName, err := s.processSchema(sub)
+		if err != nil {
+			return "", err
+		}
+		bare := strings.TrimPrefix(subTypeName, "*")
+		fieldName := s.toCamel(bare)
+		typeData += fmt.Sprintf("    %s *%s `json:\"-\" yaml:\"-\"`\n", fieldName, bare)
+		variants = append(variants, variant{fieldName: fieldName, typeName: bare})
+		mdVariants = append(mdVariants, markdownTypeRef(sub, subTypeName))
+	}
+	typeData += "}\n\n"
+
+	typeData += fmt.Sprintf("func (t *%s) UnmarshalJSON(b []byte) error {\n", typeName)
+	for _, v := range variants {
+		local := strcase.ToLowerCamel(v.fieldName)
+		typeData += fmt.Sprintf("    var %s %s\n", local, v.typeName)
+		if s.validate && s.validatedTypes[v.typeName] {
+			typeData += fmt.Sprintf("    if err := json.Unmarshal(b, &%s); err == nil {\n        if err := %s.Validate(); err == nil {\n            t.%s = &%s\n        }\n    }\n", local, local, v.fieldName, local)
+		} else {
+			typeData += fmt.Sprintf("    if err := json.Unmarshal(b, &%s); err == nil {\n        t.%s = &%s\n    }\n", local, v.fieldName, local)
+		}
+	}
+	typeData += "    return nil\n}\n\n"
+
+	typeData += fmt.Sprintf("func (t *%s) MarshalJSON() ([]byte, error) {\n    switch {\n", typeName)
+	for _, v := range variants {
+		typeData += fmt.Sprintf("    case t.%s != nil:\n        return json.Marshal(t.%s)\n", v.fieldName, v.fieldName)
+	}
+	typeData += fmt.Sprintf("    }\n    return nil, fmt.Errorf(\"%s: no anyOf variant set\")\n}\n\n", typeName)
+
+	if err := s.writeGoCode(typeName, typeData, "encoding/json", "fmt"); err != nil {
+		return "", err
+	}
+
+	if s.markdown {
+		if err := s.writeMarkdown(typeName, markdownAnyOf(schema, typeName, mdVariants)); err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("*%s", typeName), nil
+}
+
+// nullableType wraps typeExpr in a pointer if schema is OpenAPI
+// "nullable" and typeExpr isn't already nil-able (pointer, slice, or
+// map, including a named array or patternProperties map alias such as
+// "People"), so the zero value can be told apart from an explicit null.
+func nullableType(typeExpr string, schema *Schema) string {
+	if !schema.Nullable {
+		return typeExpr
+	}
+	if strings.HasPrefix(typeExpr, "*") || strings.HasPrefix(typeExpr, "[]") || strings.HasPrefix(typeExpr, "map[") {
+		return typeExpr
+	}
+	shape := schema
+	if schema.XGoType == "" && schema.Ref != "" && isNamedDefRef(schema.Ref) && schema.Root != nil {
+		if target, err := lookupRef(schema.Root, schema.Ref); err == nil {
+			shape = target
+		}
+	}
+	if shape.XGoType == "" && shape.Const == nil && len(shape.Enum) == 0 &&
+		(shape.Type == ARRAY || (shape.Type == OBJECT && shape.Properties == nil && shape.PatternProperties != nil)) {
+		return typeExpr
+	}
+	return "*" + typeExpr
+}
+
+// fieldVisibilityComment renders a trailing comment noting the OpenAPI
+// readOnly/writeOnly keywords, if set.
+func fieldVisibilityComment(schema *Schema) string {
+	switch {
+	case schema.ReadOnly:
+		return " // read-only"
+	case schema.WriteOnly:
+		return " // write-only"
+	}
+	return ""
+}
+
+// processDiscriminator generates a tagged interface for an OpenAPI
+// discriminated oneOf/anyOf: an interface with an unexported marker
+// method, one marker method implementation per named variant, and an
+// UnmarshalX dispatcher that picks the concrete type based on the
+// discriminator property.
+func (s *SchemaProcessor) processDiscriminator(schema *Schema, variants []*Schema) (string, error) {
+	typeName := s.toCamel(schema.Name())
+	typeData := fmt.Sprintf("%stype %s interface {\n    is%s()\n}\n\n", s.structComment(schema, typeName), typeName, typeName)
+
+	type variantInfo struct {
+		tag      string
+		typeName string
+	}
+	var infos []variantInfo
+	for _, v := range variants {
+		if v.Ref == "" || !isNamedDefRef(v.Ref) {
+			// Discriminated variants are expected to be named $refs;
+			// anything else is skipped since there's no tag to key it
+			// off without a schema-wide Mapping.
+			continue
+		}
+		typeExpr, err := s.resolveRef(v)
+		if err != nil {
+			return "", err
+		}
+		bare := strings.TrimPrefix(typeExpr, "*")
+
+		tag := bare
+		for value, ref := range schema.Discriminator.Mapping {
+			if strings.HasSuffix(ref, "/"+bare) {
+				tag = value
+			}
+		}
+		infos = append(infos, variantInfo{tag: tag, typeName: bare})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].tag < infos[j].tag })
+
+	for _, info := range infos {
+		typeData += fmt.Sprintf("func (*%s) is%s() {}\n\n", info.typeName, typeName)
+	}
+
+	discField := s.toCamel(schema.Discriminator.PropertyName)
+	typeData += fmt.Sprintf("func Unmarshal%s(b []byte) (%s, error) {\n", typeName, typeName)
+	typeData += fmt.Sprintf("    var d struct {\n        %s string `json:%q`\n    }\n", discField, schema.Discriminator.PropertyName)
+	typeData += "    if err := json.Unmarshal(b, &d); err != nil {\n        return nil, err\n    }\n"
+	typeData += fmt.Sprintf("    switch d.%s {\n", discField)
+	for _, info := range infos {
+		typeData += fmt.Sprintf("    case %q:\n        var v %s\n        if err := json.Unmarshal(b, &v); err != nil {\n            return nil, err\n        }\n        return &v, nil\n", info.tag, info.typeName)
+	}
+	typeData += fmt.Sprintf("    }\n    return nil, fmt.Errorf(\"unknown %s discriminator value %%q\", d.%s)\n}\n\n", typeName, discField)
+
+	if err := s.writeGoCode(typeName, typeData, "encoding/json", "fmt"); err != nil {
+		return "", err
+	}
+
+	if s.markdown {
+		tags := make([]string, len(infos))
+		typeNames := make([]string, len(infos))
+		for i, info := range infos {
+			tags[i] = info.tag
+			typeNames[i] = info.typeName
+		}
+		if err := s.writeMarkdown(typeName, markdownDiscriminator(schema, typeName, tags, typeNames)); err != nil {
+			return "", err
+		}
+	}
+	return typeName, nil
+}
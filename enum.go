@@ -0,0 +1,111 @@
+package slipscheme
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// enumKind picks the underlying Go type for a set of enum/const values
+// based on their JSON type. Mixed-type value sets fall back to string,
+// formatted via fmt.Sprint.
+func enumKind(values []any) string {
+	kind := "string"
+	for i, v := range values {
+		var k string
+		switch val := v.(type) {
+		case bool:
+			k = "bool"
+		case float64:
+			if val == math.Trunc(val) {
+				k = "int"
+			} else {
+				k = "float64"
+			}
+		default:
+			k = "string"
+		}
+		if i == 0 {
+			kind = k
+		} else if kind != k {
+			return "string"
+		}
+	}
+	return kind
+}
+
+// enumValueName renders v as the string fed into toCamel to build an enum
+// value's constant name. It's almost always just fmt.Sprint(v), except a
+// negative number is prefixed with "Neg" first - toCamel's separator
+// stripping otherwise drops the "-" and collides a negative value's name
+// with its positive counterpart (e.g. -1 and 1 both naming "...1").
+func enumValueName(v any) string {
+	if f, ok := v.(float64); ok && f < 0 {
+		return "Neg" + fmt.Sprint(-f)
+	}
+	return fmt.Sprint(v)
+}
+
+// enumLiteral renders v as a Go literal of the given enumKind.
+func enumLiteral(kind string, v any) string {
+	switch kind {
+	case "int":
+		return fmt.Sprintf("%d", int64(v.(float64)))
+	case "float64":
+		return fmt.Sprintf("%v", v.(float64))
+	case "bool":
+		return fmt.Sprintf("%v", v.(bool))
+	default:
+		return fmt.Sprintf("%q", fmt.Sprint(v))
+	}
+}
+
+// processEnum generates a distinct named type for a schema's enum (or
+// const) values: one exported constant per value plus String(),
+// MarshalJSON(), UnmarshalJSON() that reject unknown values, and a
+// Values() slice, mirroring what generators like go-jsonschema produce.
+func (s *SchemaProcessor) processEnum(schema *Schema, values []any) (string, error) {
+	typeName := s.toCamel(schema.Name())
+	kind := enumKind(values)
+
+	names := make([]string, len(values))
+	literals := make([]string, len(values))
+	for i, v := range values {
+		names[i] = fmt.Sprintf("%s%s", typeName, s.toCamel(enumValueName(v)))
+		literals[i] = enumLiteral(kind, v)
+	}
+
+	typeData := fmt.Sprintf("%stype %s %s\n\n", s.structComment(schema, typeName), typeName, kind)
+
+	typeData += "const (\n"
+	for i := range values {
+		typeData += fmt.Sprintf("    %s %s = %s\n", names[i], typeName, literals[i])
+	}
+	typeData += ")\n\n"
+
+	caseList := strings.Join(names, ", ")
+
+	typeData += fmt.Sprintf("func (e %s) String() string {\n    return fmt.Sprintf(\"%%v\", %s(e))\n}\n\n", typeName, kind)
+
+	typeData += fmt.Sprintf("func (e %s) MarshalJSON() ([]byte, error) {\n", typeName)
+	typeData += fmt.Sprintf("    switch e {\n    case %s:\n        return json.Marshal(%s(e))\n    }\n", caseList, kind)
+	typeData += fmt.Sprintf("    return nil, fmt.Errorf(\"unknown %s value %%v\", %s(e))\n}\n\n", typeName, kind)
+
+	typeData += fmt.Sprintf("func (e *%s) UnmarshalJSON(b []byte) error {\n", typeName)
+	typeData += fmt.Sprintf("    var v %s\n    if err := json.Unmarshal(b, &v); err != nil {\n        return err\n    }\n", kind)
+	typeData += fmt.Sprintf("    switch %s(v) {\n    case %s:\n        *e = %s(v)\n        return nil\n    }\n", typeName, caseList, typeName)
+	typeData += fmt.Sprintf("    return fmt.Errorf(\"unknown %s value %%v\", v)\n}\n\n", typeName)
+
+	typeData += fmt.Sprintf("func (%s) Values() []%s {\n    return []%s{%s}\n}\n\n", typeName, typeName, typeName, caseList)
+
+	if err := s.writeGoCode(typeName, typeData, "encoding/json", "fmt"); err != nil {
+		return "", err
+	}
+
+	if s.markdown {
+		if err := s.writeMarkdown(typeName, markdownEnum(schema, typeName, names, literals)); err != nil {
+			return "", err
+		}
+	}
+	return typeName, nil
+}
@@ -0,0 +1,237 @@
+package slipscheme
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Markdown enables a companion Markdown reference doc (one per named
+// type, alongside its .go file) generated from the same parsed Schema
+// tree so code and docs stay in lockstep. The struct that merges the
+// variants of a oneOf without a discriminator gets no Markdown doc.
+func Markdown(markdown bool) SchemaProcessorOption {
+	return func(s *SchemaProcessor) {
+		s.markdown = markdown
+	}
+}
+
+// markdownAnchor renders name as the anchor GitHub's Markdown renderer
+// derives for a "## name" heading, so cross-reference links keep working
+// without a separate slug table.
+func markdownAnchor(name string) string {
+	return strings.ToLower(name)
+}
+
+// markdownTypeRef renders the Markdown representation of a property's
+// type: a link to another generated type's section when v resolves to
+// one (a named $ref, an inline object, an enum/const, an allOf/anyOf
+// composition, a discriminated interface, or a named array/map alias),
+// or the bare Go type expression otherwise.
+func markdownTypeRef(v *Schema, goType string) string {
+	bare := strings.TrimPrefix(goType, "*")
+	bare = strings.TrimPrefix(bare, "[]")
+	bare = strings.TrimPrefix(bare, "map[string]")
+
+	named := (v.Type == OBJECT && v.Properties != nil) || v.Const != nil || len(v.Enum) > 0 ||
+		len(v.AllOf) > 0 || len(v.AnyOf) > 0 || v.Discriminator != nil ||
+		(v.Type == OBJECT && v.PatternProperties != nil) ||
+		(v.Type == ARRAY && v.Name() != "")
+	if v.Ref != "" && isNamedDefRef(v.Ref) {
+		named = true
+	}
+	if !named || bare == "" || !isTitleCase(bare) {
+		return fmt.Sprintf("`%s`", goType)
+	}
+	return fmt.Sprintf("[`%s`](#%s)", goType, markdownAnchor(bare))
+}
+
+// markdownConstraints summarizes a property's validation keywords into a
+// short comma-separated list for the reference table, or "—" if none
+// apply.
+func markdownConstraints(v *Schema) string {
+	var parts []string
+	if v.MinLength != nil {
+		parts = append(parts, fmt.Sprintf("minLength: %d", *v.MinLength))
+	}
+	if v.MaxLength != nil {
+		parts = append(parts, fmt.Sprintf("maxLength: %d", *v.MaxLength))
+	}
+	if v.Pattern != "" {
+		parts = append(parts, fmt.Sprintf("pattern: `%s`", v.Pattern))
+	}
+	if v.Minimum != nil {
+		parts = append(parts, fmt.Sprintf("minimum: %v", *v.Minimum))
+	}
+	if v.Maximum != nil {
+		parts = append(parts, fmt.Sprintf("maximum: %v", *v.Maximum))
+	}
+	if v.ExclusiveMinimum != nil {
+		parts = append(parts, fmt.Sprintf("exclusiveMinimum: %v", *v.ExclusiveMinimum))
+	}
+	if v.ExclusiveMaximum != nil {
+		parts = append(parts, fmt.Sprintf("exclusiveMaximum: %v", *v.ExclusiveMaximum))
+	}
+	if v.MultipleOf != nil {
+		parts = append(parts, fmt.Sprintf("multipleOf: %v", *v.MultipleOf))
+	}
+	if v.MinItems != nil {
+		parts = append(parts, fmt.Sprintf("minItems: %d", *v.MinItems))
+	}
+	if v.MaxItems != nil {
+		parts = append(parts, fmt.Sprintf("maxItems: %d", *v.MaxItems))
+	}
+	if v.Format != "" {
+		parts = append(parts, fmt.Sprintf("format: %s", v.Format))
+	}
+	if len(parts) == 0 {
+		return "—"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// markdownRow renders one property's row of a type's reference table.
+func markdownRow(jsonName string, v *Schema, goType string, required bool) string {
+	req := ""
+	if required {
+		req = "yes"
+	}
+	def := "—"
+	if v.Default != nil {
+		def = fmt.Sprintf("`%v`", v.Default)
+	}
+	return fmt.Sprintf("| `%s` | %s | %s | %s | %s | %s |\n", jsonName, markdownTypeRef(v, goType), req, v.Description, def, markdownConstraints(v))
+}
+
+// markdownExamples renders a schema's "default" and "examples" values (if
+// any) under an Examples heading, one fenced JSON code block per value.
+func markdownExamples(schema *Schema) string {
+	var values []any
+	if schema.Default != nil {
+		values = append(values, schema.Default)
+	}
+	values = append(values, schema.Examples...)
+	if len(values) == 0 {
+		return ""
+	}
+
+	md := "**Examples:**\n\n"
+	for _, v := range values {
+		b, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			continue
+		}
+		md += fmt.Sprintf("```json\n%s\n```\n\n", b)
+	}
+	return md
+}
+
+// markdownType renders a struct's reference section: heading, description,
+// a property table, and any examples.
+func markdownType(schema *Schema, typeName string, rows []string) string {
+	md := fmt.Sprintf("## %s\n\n", typeName)
+	if schema.Description != "" {
+		md += schema.Description + "\n\n"
+	}
+	md += "| Property | Type | Required | Description | Default | Constraints |\n"
+	md += "| --- | --- | --- | --- | --- | --- |\n"
+	for _, row := range rows {
+		md += row
+	}
+	md += "\n" + markdownExamples(schema)
+	return md
+}
+
+// markdownAllOf renders an allOf-composed struct's reference section:
+// heading, description, a line linking to each anonymously-embedded
+// member's own section (its fields are real, embedded JSON fields on
+// this type but aren't repeated here), a property table for this type's
+// own merged/inline properties, and any examples.
+func markdownAllOf(schema *Schema, typeName string, embeds, rows []string) string {
+	md := fmt.Sprintf("## %s\n\n", typeName)
+	if schema.Description != "" {
+		md += schema.Description + "\n\n"
+	}
+	for _, e := range embeds {
+		md += fmt.Sprintf("Embeds %s.\n\n", e)
+	}
+	md += "| Property | Type | Required | Description | Default | Constraints |\n"
+	md += "| --- | --- | --- | --- | --- | --- |\n"
+	for _, row := range rows {
+		md += row
+	}
+	md += "\n" + markdownExamples(schema)
+	return md
+}
+
+// markdownEnum renders an enum/const type's reference section: heading,
+// description, the list of allowed values, and any examples.
+func markdownEnum(schema *Schema, typeName string, names, literals []string) string {
+	md := fmt.Sprintf("## %s\n\n", typeName)
+	if schema.Description != "" {
+		md += schema.Description + "\n\n"
+	}
+	md += "| Constant | Value |\n"
+	md += "| --- | --- |\n"
+	for i, name := range names {
+		md += fmt.Sprintf("| `%s` | `%s` |\n", name, literals[i])
+	}
+	md += "\n" + markdownExamples(schema)
+	return md
+}
+
+// markdownAnyOf renders an anyOf wrapper type's reference section:
+// heading, description, and the list of possible variant types.
+func markdownAnyOf(schema *Schema, typeName string, variants []string) string {
+	md := fmt.Sprintf("## %s\n\n", typeName)
+	if schema.Description != "" {
+		md += schema.Description + "\n\n"
+	}
+	md += "One of:\n\n"
+	for _, v := range variants {
+		md += fmt.Sprintf("- %s\n", v)
+	}
+	md += "\n" + markdownExamples(schema)
+	return md
+}
+
+// markdownDiscriminator renders a discriminated interface type's
+// reference section: heading, description, and a table mapping each
+// discriminator value to its concrete variant type.
+func markdownDiscriminator(schema *Schema, typeName string, tags, variants []string) string {
+	md := fmt.Sprintf("## %s\n\n", typeName)
+	if schema.Description != "" {
+		md += schema.Description + "\n\n"
+	}
+	md += "| Discriminator value | Type |\n"
+	md += "| --- | --- |\n"
+	for i, tag := range tags {
+		md += fmt.Sprintf("| `%s` | [`%s`](#%s) |\n", tag, variants[i], markdownAnchor(variants[i]))
+	}
+	md += "\n" + markdownExamples(schema)
+	return md
+}
+
+// markdownArray renders a named array type's reference section:
+// heading, description, and the element type.
+func markdownArray(schema *Schema, typeName, elemType string) string {
+	md := fmt.Sprintf("## %s\n\n", typeName)
+	if schema.Description != "" {
+		md += schema.Description + "\n\n"
+	}
+	md += fmt.Sprintf("Array of %s.\n\n", elemType)
+	md += markdownExamples(schema)
+	return md
+}
+
+// markdownMap renders a named patternProperties map type's reference
+// section: heading, description, and the value type.
+func markdownMap(schema *Schema, typeName, valueType string) string {
+	md := fmt.Sprintf("## %s\n\n", typeName)
+	if schema.Description != "" {
+		md += schema.Description + "\n\n"
+	}
+	md += fmt.Sprintf("Map of string to %s.\n\n", valueType)
+	md += markdownExamples(schema)
+	return md
+}
@@ -0,0 +1,125 @@
+package slipscheme
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Discriminator is the OpenAPI "discriminator" keyword: it names the
+// property used to tell which oneOf/anyOf variant a payload is, with an
+// optional explicit value->$ref mapping.
+type Discriminator struct {
+	PropertyName string            `json:"propertyName,omitempty"`
+	Mapping      map[string]string `json:"mapping,omitempty"`
+}
+
+// detectInputFormat reports OpenAPIInput if data has a top-level
+// "openapi" (3.x) or "swagger" (2.0) key, and JSONSchemaInput otherwise.
+func detectInputFormat(data []byte) InputFormat {
+	var probe struct {
+		OpenAPI string `json:"openapi"`
+		Swagger string `json:"swagger"`
+	}
+	if err := json.Unmarshal(data, &probe); err == nil {
+		if probe.OpenAPI != "" || probe.Swagger != "" {
+			return OpenAPIInput
+		}
+	}
+	return JSONSchemaInput
+}
+
+// processOpenAPI ingests an OpenAPI 3.x or Swagger 2.0 document,
+// generating one Go type for every entry of components.schemas (or
+// definitions, for Swagger 2.0), reusing the normal $ref-based named
+// type machinery for cross-references between them.
+func (s *SchemaProcessor) processOpenAPI(data []byte) error {
+	var doc struct {
+		Components struct {
+			Schemas map[string]json.RawMessage `json:"schemas"`
+		} `json:"components"`
+		Definitions map[string]json.RawMessage `json:"definitions"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+
+	schemas := doc.Components.Schemas
+	if len(schemas) == 0 {
+		schemas = doc.Definitions
+	}
+	if len(schemas) == 0 {
+		return fmt.Errorf("no components.schemas or definitions found in OpenAPI document")
+	}
+
+	// Wrap the component schemas as "definitions" on a synthetic root
+	// so the existing #/definitions/X named-type resolution (resolveRef)
+	// can be reused as-is. $refs of the form "#/components/schemas/X"
+	// are rewritten to "#/definitions/X" to match.
+	rewritten := bytes.ReplaceAll(data, []byte(`"#/components/schemas/`), []byte(`"#/definitions/`))
+
+	var rewrittenDoc struct {
+		Components struct {
+			Schemas map[string]json.RawMessage `json:"schemas"`
+		} `json:"components"`
+		Definitions map[string]json.RawMessage `json:"definitions"`
+	}
+	if err := json.Unmarshal(rewritten, &rewrittenDoc); err != nil {
+		return err
+	}
+	defs := rewrittenDoc.Components.Schemas
+	if len(defs) == 0 {
+		defs = rewrittenDoc.Definitions
+	}
+
+	root := struct {
+		Type        string                     `json:"type"`
+		Definitions map[string]json.RawMessage `json:"definitions"`
+	}{
+		Type:        "object",
+		Definitions: defs,
+	}
+	rootData, err := json.Marshal(root)
+	if err != nil {
+		return err
+	}
+
+	schema, err := s.ParseSchema(rootData)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(schema.Definitions))
+	for name := range schema.Definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, err := s.resolveRef(&Schema{Ref: "#/definitions/" + name, Root: schema}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InputFormat selects how Process interprets each input file.
+type InputFormat int
+
+const (
+	// AutoDetect inspects each document for a top-level "openapi" or
+	// "swagger" key and falls back to plain JSON Schema.
+	AutoDetect InputFormat = iota
+	// JSONSchemaInput forces plain JSON Schema parsing.
+	JSONSchemaInput
+	// OpenAPIInput forces OpenAPI 3.x / Swagger 2.0 parsing.
+	OpenAPIInput
+)
+
+// WithInputFormat overrides input format auto-detection.
+func WithInputFormat(format InputFormat) SchemaProcessorOption {
+	return func(s *SchemaProcessor) {
+		s.inputFormat = format
+	}
+}
@@ -1,25 +1,30 @@
 package slipscheme
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"go/format"
 	"io"
-	"log"
 	"os"
-	"os/exec"
-	"path"
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"unicode"
 
 	"github.com/iancoleman/strcase"
+	"golang.org/x/sync/errgroup"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
 
 var (
-	caser               = cases.Title(language.Und)
+	caser = cases.Title(language.Und)
+	// caserMu guards caser: golang.org/x/text/cases.Caser isn't
+	// documented as safe for concurrent use, and Process now calls into
+	// processSchema from multiple goroutines, see (*SchemaProcessor).forFile.
+	caserMu             sync.Mutex
 	defaultReplacements = map[string]string{
 		"Id":    "ID",
 		"Http":  "HTTP",
@@ -32,6 +37,14 @@ var (
 	}
 )
 
+// isTitleCase reports whether s is already title-cased, i.e. unchanged
+// by caser, guarded by caserMu since caser itself isn't.
+func isTitleCase(s string) bool {
+	caserMu.Lock()
+	defer caserMu.Unlock()
+	return caser.String(s) == s
+}
+
 // Stdio holds common io readers/writers
 type Stdio struct {
 	Stdin  io.Reader
@@ -46,15 +59,63 @@ type Schema struct {
 	Type                 SchemaType         `json:"type,omitempty"`
 	Description          string             `json:"description,omitempty"`
 	Definitions          map[string]*Schema `json:"definitions,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
 	Properties           map[string]*Schema `json:"properties,omitempty"`
 	AdditionalProperties bool               `json:"additionalProperties,omitempty"`
 	PatternProperties    map[string]*Schema `json:"patternProperties,omitempty"`
 	Ref                  string             `json:"$ref,omitempty"`
 	Items                *Schema            `json:"items,omitempty"`
 	OneOf                []*Schema          `json:"oneOf,omitempty"`
-	Const                string             `json:"const,omitempty"`
-	Enum                 []string           `json:"enum,omitempty"`
+	Const                any                `json:"const,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
 	Root                 *Schema            `json:"-"`
+
+	// Validation keywords. Validate() methods enforce them only when the
+	// SchemaProcessor has validation generation enabled, see
+	// WithValidation. Markdown docs list the constraints and the required
+	// flag regardless, and AllOf and AnyOf always shape the generated types.
+	MinLength        *int      `json:"minLength,omitempty"`
+	MaxLength        *int      `json:"maxLength,omitempty"`
+	Pattern          string    `json:"pattern,omitempty"`
+	Minimum          *float64  `json:"minimum,omitempty"`
+	Maximum          *float64  `json:"maximum,omitempty"`
+	ExclusiveMinimum *float64  `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum *float64  `json:"exclusiveMaximum,omitempty"`
+	MultipleOf       *float64  `json:"multipleOf,omitempty"`
+	MinItems         *int      `json:"minItems,omitempty"`
+	MaxItems         *int      `json:"maxItems,omitempty"`
+	UniqueItems      bool      `json:"uniqueItems,omitempty"`
+	MinProperties    *int      `json:"minProperties,omitempty"`
+	MaxProperties    *int      `json:"maxProperties,omitempty"`
+	Required         []string  `json:"required,omitempty"`
+	Format           string    `json:"format,omitempty"`
+	AllOf            []*Schema `json:"allOf,omitempty"`
+	AnyOf            []*Schema `json:"anyOf,omitempty"`
+	Not              *Schema   `json:"not,omitempty"`
+	If               *Schema   `json:"if,omitempty"`
+	Then             *Schema   `json:"then,omitempty"`
+	Else             *Schema   `json:"else,omitempty"`
+	// Dependencies is left as a raw map because the "dependencies"
+	// keyword is overloaded in the spec: a value is either a list of
+	// property names (dependentRequired) or a nested schema
+	// (dependentSchemas).
+	Dependencies map[string]json.RawMessage `json:"dependencies,omitempty"`
+
+	// OpenAPI/Swagger extensions. These are parsed and honoured for any
+	// input document, though they normally come from documents read
+	// through the openapi InputFormat, see WithInputFormat.
+	Nullable      bool           `json:"nullable,omitempty"`
+	ReadOnly      bool           `json:"readOnly,omitempty"`
+	WriteOnly     bool           `json:"writeOnly,omitempty"`
+	Discriminator *Discriminator `json:"discriminator,omitempty"`
+	XGoType       string         `json:"x-go-type,omitempty"`
+	XGoName       string         `json:"x-go-name,omitempty"`
+
+	// Default and Examples are surfaced in generated Markdown docs, see
+	// Markdown.
+	Default  any   `json:"default,omitempty"`
+	Examples []any `json:"examples,omitempty"`
+
 	// only populated on Root node
 	raw map[string]any
 }
@@ -165,9 +226,46 @@ type SchemaProcessor struct {
 	stdout       bool
 	format       bool
 	comment      bool
+	markdown     bool
 	stdio        Stdio
 	replacements map[string]string
 	processed    map[string]bool
+	validate     bool
+	// refTypes maps a "#/definitions/Foo" or "#/$defs/Foo" ref to the
+	// Go type expression already generated for it (e.g. "*Foo"), so
+	// every use site references the same named type instead of
+	// re-emitting it, and so cyclic definitions terminate.
+	refTypes map[string]string
+	// validatedTypes records the bare (unprefixed) name of every struct
+	// type that got its own Validate() method, so a property of that
+	// type - or a slice/map of it - knows to cascade into it instead of
+	// silently skipping its checks. Populated alongside every Validate()
+	// emitted by processSchema's object branch and processAllOf.
+	validatedTypes map[string]bool
+	// arrayElemTypes and mapValueTypes map a named array/map alias (e.g.
+	// "People", generated as "type People []*Person") to its element/value
+	// type expression, so nestedValidateCheck can see through the named
+	// alias to decide whether to cascade into it, the same as it would
+	// for an inline "[]*Person" or "map[string]*Person" field type.
+	arrayElemTypes map[string]string
+	mapValueTypes  map[string]string
+	backend  OutputBackend
+	// formatter runs over the fully assembled file (preamble + code)
+	// before it's handed to backend, when Format(true) is set. Defaults
+	// to go/format.Source; pass Formatter(...) to use goimports instead.
+	formatter func([]byte) ([]byte, error)
+	// inputFormat controls whether Process treats a document as plain
+	// JSON Schema or as an OpenAPI/Swagger document, see
+	// WithInputFormat.
+	inputFormat InputFormat
+	// registry collects generated files from every file Process parses,
+	// shared across the per-file SchemaProcessor views forFile hands to
+	// each parse goroutine, so it can tell a legitimate re-emission of
+	// the same type apart from two input files disagreeing about it.
+	registry *outputRegistry
+	// currentFile is the input file the current SchemaProcessor view is
+	// parsing, set by forFile and used to label registry conflicts.
+	currentFile string
 }
 
 type SchemaProcessorOption func(*SchemaProcessor)
@@ -238,41 +336,122 @@ func NewSchemaProcessor(options ...SchemaProcessorOption) *SchemaProcessor {
 		s.replacements[k] = v
 	}
 
+	if s.formatter == nil {
+		s.formatter = format.Source
+	}
+
+	if s.backend == nil {
+		if s.stdout {
+			s.backend = &StdoutOutputBackend{Out: s.stdio.Stdout}
+		} else {
+			s.backend = &FileOutputBackend{Dir: s.outputDir, Overwrite: s.overwrite}
+		}
+	}
+
+	s.registry = &outputRegistry{}
+
 	return s
 }
 
-// Process will read a list of json schema files, parse them
-// and write them to the OutputDir
+// Process reads a list of json schema files and parses them concurrently
+// into an in-memory registry of generated files, detecting when two
+// input files disagree about the content of a type they both generate;
+// once every file has parsed cleanly, the registry is flushed to the
+// OutputBackend in sorted order so output is deterministic regardless of
+// parse goroutine scheduling.
 func (s *SchemaProcessor) Process(files []string) error {
-	for _, file := range files {
+	type input struct {
+		name string
+		data []byte
+	}
+
+	inputs := make([]input, len(files))
+	for i, file := range files {
 		var r io.Reader
-		var b []byte
 		if file == "-" {
 			r = s.stdio.Stdin
 		} else {
 			fh, err := os.OpenFile(file, os.O_RDONLY, 0o644)
-			defer fh.Close()
 			if err != nil {
 				return err
 			}
+			defer fh.Close()
 			r = fh
 		}
+
 		b, err := io.ReadAll(r)
 		if err != nil {
 			return err
 		}
+		inputs[i] = input{name: file, data: b}
+	}
 
-		schema, err := s.ParseSchema(b)
-		if err != nil {
-			return err
-		}
+	g := new(errgroup.Group)
+	for _, in := range inputs {
+		in := in
+		g.Go(func() error {
+			return s.forFile(in.name).processFile(in.data)
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
 
-		_, err = s.processSchema(schema)
-		if err != nil {
+	return s.flushRegistry()
+}
+
+// forFile returns a SchemaProcessor view scoped to a single input file:
+// a shallow copy sharing the parent's configuration, OutputBackend, and
+// registry, but with its own processed/refTypes caches so concurrent
+// parses of different files don't race on each other's $ref bookkeeping.
+func (s *SchemaProcessor) forFile(file string) *SchemaProcessor {
+	clone := *s
+	clone.processed = map[string]bool{}
+	clone.refTypes = map[string]string{}
+	clone.validatedTypes = map[string]bool{}
+	clone.arrayElemTypes = map[string]string{}
+	clone.mapValueTypes = map[string]string{}
+	clone.currentFile = file
+	return &clone
+}
+
+// processFile parses a single input document and generates its types,
+// auto-detecting JSON Schema vs. OpenAPI/Swagger unless overridden by
+// WithInputFormat.
+func (s *SchemaProcessor) processFile(data []byte) error {
+	format := s.inputFormat
+	if format == AutoDetect {
+		format = detectInputFormat(data)
+	}
+
+	if format == OpenAPIInput {
+		return s.processOpenAPI(data)
+	}
+
+	schema, err := s.ParseSchema(data)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.processSchema(schema)
+	return err
+}
+
+// flushRegistry writes every generated file collected in the registry to
+// the OutputBackend in sorted path order.
+func (s *SchemaProcessor) flushRegistry() error {
+	paths := make([]string, 0, len(s.registry.files))
+	for path := range s.registry.files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		if err := s.backend.WriteFile(path, s.registry.files[path].contents); err != nil {
 			return err
 		}
 	}
-	return nil
+	return s.backend.Finalize()
 }
 
 // ParseSchema simply parses the schema and post-processes the objects
@@ -305,6 +484,42 @@ func (s *SchemaProcessor) structComment(schema *Schema, typeName string) string
 }
 
 func (s *SchemaProcessor) processSchema(schema *Schema) (typeName string, err error) {
+	// x-go-type is a vendor extension that overrides the generated type
+	// outright, e.g. to point a property at a hand-written type.
+	if schema.XGoType != "" {
+		return schema.XGoType, nil
+	}
+
+	if schema.Ref != "" && isNamedDefRef(schema.Ref) {
+		return s.resolveRef(schema)
+	}
+
+	if schema.Discriminator != nil {
+		variants := schema.OneOf
+		if len(variants) == 0 {
+			variants = schema.AnyOf
+		}
+		if len(variants) > 0 {
+			return s.processDiscriminator(schema, variants)
+		}
+	}
+
+	if len(schema.AllOf) > 0 {
+		return s.processAllOf(schema)
+	}
+	if len(schema.AnyOf) > 0 {
+		return s.processAnyOf(schema)
+	}
+
+	// enum/const take priority over the base "type", since a schema
+	// commonly declares both (e.g. {"type": "string", "enum": [...]}).
+	switch {
+	case schema.Const != nil:
+		return s.processEnum(schema, []any{schema.Const})
+	case len(schema.Enum) > 0:
+		return s.processEnum(schema, schema.Enum)
+	}
+
 	switch schema.Type {
 	case OBJECT:
 		typeName = s.toCamel(schema.Name())
@@ -316,18 +531,67 @@ func (s *SchemaProcessor) processSchema(schema *Schema) (typeName string, err er
 				keys = append(keys, k)
 			}
 			sort.Strings(keys)
+
+			required := map[string]bool{}
+			for _, r := range schema.Required {
+				required[r] = true
+			}
+
+			var checks []string
+			var imports []string
+			var mdRows []string
 			for _, k := range keys {
 				v := schema.Properties[k]
 				subTypeName, err := s.processSchema(v)
 				if err != nil {
 					return "", err
 				}
-				typeData += fmt.Sprintf("    %s %s `json:\"%s,omitempty\" yaml:\"%s,omitempty\"`\n", s.toCamel(k), subTypeName, k, k)
+				wrappedTypeName := nullableType(subTypeName, v)
+				nullable := wrappedTypeName != subTypeName
+				subTypeName = wrappedTypeName
+				fieldName := s.toCamel(k)
+				if v.XGoName != "" {
+					fieldName = s.toCamel(v.XGoName)
+				}
+				typeData += fmt.Sprintf("    %s %s `json:\"%s,omitempty\" yaml:\"%s,omitempty\"`%s\n", fieldName, subTypeName, k, k, fieldVisibilityComment(v))
+				if s.validate {
+					fieldChecks, fieldImports := s.validationChecks(fieldName, k, v, required[k], nullable)
+					checks = append(checks, fieldChecks...)
+					imports = append(imports, fieldImports...)
+					if nested := nestedValidateCheck(fieldName, k, subTypeName, s.validatedTypes, s.arrayElemTypes, s.mapValueTypes); nested != "" {
+						checks = append(checks, nested)
+					}
+				}
+				if s.markdown {
+					mdRows = append(mdRows, markdownRow(k, v, subTypeName, required[k]))
+				}
 			}
 			typeData += "}\n\n"
-			if err := s.writeGoCode(typeName, typeData); err != nil {
+
+			if s.validate {
+				typeData += fmt.Sprintf("func (t *%s) Validate() error {\n", typeName)
+				for _, check := range checks {
+					typeData += check
+				}
+				typeData += "    return nil\n}\n\n"
+				if len(checks) > 0 {
+					imports = append(imports, "fmt")
+				}
+				if s.validatedTypes == nil {
+					s.validatedTypes = map[string]bool{}
+				}
+				s.validatedTypes[typeName] = true
+			}
+
+			if err := s.writeGoCode(typeName, typeData, imports...); err != nil {
 				return "", err
 			}
+
+			if s.markdown {
+				if err := s.writeMarkdown(typeName, markdownType(schema, typeName, mdRows)); err != nil {
+					return "", err
+				}
+			}
 			typeName = fmt.Sprintf("*%s", typeName)
 		case schema.PatternProperties != nil:
 			keys := []string{}
@@ -343,12 +607,21 @@ func (s *SchemaProcessor) processSchema(schema *Schema) (typeName string, err er
 				}
 
 				// verify subTypeName is not a simple type
-				if caser.String(subTypeName) == subTypeName {
+				if isTitleCase(subTypeName) {
 					typeName = strings.TrimPrefix(fmt.Sprintf("%sMap", subTypeName), "*")
 					typeData := fmt.Sprintf("%stype %s map[string]%s\n\n", s.structComment(schema, typeName), typeName, subTypeName)
 					if err := s.writeGoCode(typeName, typeData); err != nil {
 						return "", err
 					}
+					if s.markdown {
+						if err := s.writeMarkdown(typeName, markdownMap(schema, typeName, markdownTypeRef(v, subTypeName))); err != nil {
+							return "", err
+						}
+					}
+					if s.mapValueTypes == nil {
+						s.mapValueTypes = map[string]string{}
+					}
+					s.mapValueTypes[typeName] = subTypeName
 				} else {
 					typeName = fmt.Sprintf("map[string]%s", subTypeName)
 				}
@@ -365,7 +638,7 @@ func (s *SchemaProcessor) processSchema(schema *Schema) (typeName string, err er
 
 		typeName = s.toCamel(schema.Name())
 		if typeName == "" {
-			if caser.String(subTypeName) == subTypeName {
+			if isTitleCase(subTypeName) {
 				if strings.HasSuffix(subTypeName, "s") {
 					typeName = fmt.Sprintf("%ses", subTypeName)
 				} else {
@@ -379,19 +652,21 @@ func (s *SchemaProcessor) processSchema(schema *Schema) (typeName string, err er
 			if err := s.writeGoCode(typeName, typeData); err != nil {
 				return "", err
 			}
+			if s.markdown {
+				if err := s.writeMarkdown(typeName, markdownArray(schema, typeName, markdownTypeRef(schema.Items, subTypeName))); err != nil {
+					return "", err
+				}
+			}
+			if s.arrayElemTypes == nil {
+				s.arrayElemTypes = map[string]string{}
+			}
+			s.arrayElemTypes[typeName] = subTypeName
 		} else {
 			typeName = fmt.Sprintf("[]%s", subTypeName)
 		}
 	case ANY:
-		switch {
-		case len(schema.OneOf) > 0:
+		if len(schema.OneOf) > 0 {
 			return s.mergeSchemas(schema, schema.OneOf...)
-		case schema.Const != "":
-			// Const is a special case of Enum
-			return "string", nil
-		case len(schema.Enum) > 0:
-			// TODO this is bogus, but assuming Enums are string types for now
-			return "string", nil
 		}
 		typeName = "any"
 	case BOOLEAN:
@@ -408,153 +683,93 @@ func (s *SchemaProcessor) processSchema(schema *Schema) (typeName string, err er
 	return
 }
 
-func (s *SchemaProcessor) mergeSchemas(parent *Schema, schemas ...*Schema) (typeName string, err error) {
-	switch len(schemas) {
-	case 0:
-		return "", fmt.Errorf("merging zero schemas")
-	case 1:
-		// TODO: Not sure this is correct, should the name come from the oneOf
-		// schema or the only constraint schema?
-		return s.processSchema(schemas[0])
-	}
-
-	mergedParent := &Schema{
-		Description: parent.Name(),
-		Root:        parent.Root,
-		Properties:  map[string]*Schema{},
-		Type:        OBJECT,
-	}
-
-	uncommonSchemas := map[string]*Schema{}
-	for _, schema := range schemas {
-		// TODO we need a Schema.Copy() function
-		uncommonSchemas[schema.Name()] = &Schema{
-			Description: schema.Name(),
-			Root:        parent.Root,
-			Properties:  map[string]*Schema{},
-			Type:        schema.Type,
-		}
+func (s *SchemaProcessor) writeGoCode(typeName, code string, imports ...string) error {
+	if seen, ok := s.processed[typeName]; ok && seen {
+		return nil
 	}
-
-	// find any common properties, and assign them to mergeParent
-	// else create subtype with uncommon properties with `json:",inline"`
-
-	allProperties := map[string]int{}
-	for _, schema := range schemas {
-		for p := range schema.Properties {
-			allProperties[p]++
+	// mark schemas as processed so we dont print/write it out again
+	if s.processed == nil {
+		s.processed = map[string]bool{
+			typeName: true,
 		}
+	} else {
+		s.processed[typeName] = true
 	}
 
-	for _, schema := range schemas {
-		for p, v := range schema.Properties {
-			if allProperties[p] > 1 {
-				mergedParent.Properties[p] = v
-			} else {
-				uncommonSchemas[schema.Name()].Properties[p] = v
-			}
-		}
-	}
+	preamble := "// Code generated by github.com/rdeusser/slipscheme DO NOT EDIT.\n"
+	preamble += fmt.Sprintf("package %s\n", s.packageName)
 
-	typeName = s.toCamel(mergedParent.Name())
-	typeData := fmt.Sprintf("%stype %s struct {\n", s.structComment(mergedParent, typeName), typeName)
+	contents := []byte(preamble + importBlock(imports) + code)
 
-	keys := []string{}
-	for k := range mergedParent.Properties {
-		keys = append(keys, k)
-	}
-	sort.Strings(keys)
-	for _, k := range keys {
-		v := mergedParent.Properties[k]
-		subTypeName, err := s.processSchema(v)
+	if s.format {
+		formatted, err := s.formatter(contents)
 		if err != nil {
-			return "", err
+			return err
 		}
-		typeData += fmt.Sprintf("    %s %s `json:\"%s,omitempty\" yaml:\"%s,omitempty\"`\n", s.toCamel(k), subTypeName, k, k)
+		contents = formatted
 	}
 
-	oneOfKeys := []string{}
-	for name, schema := range uncommonSchemas {
-		if len(schema.Properties) > 0 {
-			oneOfKeys = append(oneOfKeys, name)
-		}
-	}
-	sort.Strings(oneOfKeys)
+	filename := fmt.Sprintf("%s.go", strcase.ToSnakeWithIgnore(typeName, "Id"))
+	return s.registry.put(filename, s.currentFile, contents)
+}
 
-	for _, k := range oneOfKeys {
-		oneOfTypeName, err := s.processSchema(uncommonSchemas[k])
-		if err != nil {
-			return "", err
-		}
-		typeData += fmt.Sprintf("    %s %s `json:\",inline\" yaml:\",inline\"`\n", s.toCamel(k), oneOfTypeName)
-	}
+// writeMarkdown writes a type's companion reference doc alongside its
+// generated .go file, named the same way.
+func (s *SchemaProcessor) writeMarkdown(typeName, content string) error {
+	filename := fmt.Sprintf("%s.md", strcase.ToSnakeWithIgnore(typeName, "Id"))
+	return s.registry.put(filename, s.currentFile, []byte(content))
+}
 
-	typeData += "}\n\n"
-	if err := s.writeGoCode(typeName, typeData); err != nil {
-		return "", err
-	}
-	return typeName, nil
+// outputRegistry collects generated files from every per-file parse
+// Process runs concurrently, before anything is handed to the
+// OutputBackend. This lets Process detect two input files producing
+// divergent content for the same generated filename, instead of the
+// last write silently winning.
+type outputRegistry struct {
+	mu    sync.Mutex
+	files map[string]registryEntry
 }
 
-func (s *SchemaProcessor) writeGoCode(typeName, code string) error {
-	if seen, ok := s.processed[typeName]; ok && seen {
-		return nil
-	}
-	// mark schemas as processed so we dont print/write it out again
-	if s.processed == nil {
-		s.processed = map[string]bool{
-			typeName: true,
-		}
-	} else {
-		s.processed[typeName] = true
-	}
+// registryEntry is one file collected in an outputRegistry, along with
+// the input file it was generated from, for conflict error messages.
+type registryEntry struct {
+	source   string
+	contents []byte
+}
 
-	if s.stdout {
-		if s.format {
-			cmd := exec.Command("gofmt", "-s")
-			inPipe, _ := cmd.StdinPipe()
-			cmd.Stdout = s.stdio.Stdout
-			cmd.Stderr = s.stdio.Stderr
-			cmd.Start()
-			inPipe.Write([]byte(code))
-			inPipe.Close()
-			return cmd.Wait()
-		}
-		fmt.Print(code)
-		return nil
-	}
-	file := path.Join(s.outputDir, fmt.Sprintf("%s.go", strcase.ToSnakeWithIgnore(typeName, "Id")))
-	if !s.overwrite {
-		if _, err := os.Stat(file); err == nil {
-			log.Printf("File %s already exists, skipping without -overwrite", file)
+// put records path as generated from source with the given contents. A
+// second put for the same path is only an error if its contents differ
+// from what's already recorded; identical re-emission (e.g. a shared
+// $ref processed from more than one place) is not a conflict.
+func (r *outputRegistry) put(path, source string, contents []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.files[path]; ok {
+		if bytes.Equal(existing.contents, contents) {
 			return nil
 		}
+		return fmt.Errorf("type %s has divergent definitions in %s and %s:\n%s", path, existing.source, source, diffPreview(existing.contents, contents))
 	}
-	fh, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
-	if err != nil {
-		return err
-	}
-	defer fh.Close()
-	preamble := "// Code generated by github.com/rdeusser/slipscheme DO NOT EDIT.\n"
-	preamble += fmt.Sprintf("package %s\n", s.packageName)
-	fmt.Printf("Writing %s\n", file)
 
-	if _, err := fh.Write([]byte(preamble)); err != nil {
-		return err
-	}
-	if _, err := fh.Write([]byte(code)); err != nil {
-		return err
+	if r.files == nil {
+		r.files = map[string]registryEntry{}
 	}
+	r.files[path] = registryEntry{source: source, contents: contents}
+	return nil
+}
 
-	if s.format {
-		cmd := exec.Command("gofmt", "-s", "-w", file)
-		cmd.Stdin = s.stdio.Stdin
-		cmd.Stdout = s.stdio.Stdout
-		cmd.Stderr = s.stdio.Stderr
-		return cmd.Run()
+// diffPreview returns a short line-based preview of where a and b first
+// differ, for outputRegistry conflict error messages.
+func diffPreview(a, b []byte) string {
+	aLines := strings.Split(string(a), "\n")
+	bLines := strings.Split(string(b), "\n")
+	for i := 0; i < len(aLines) && i < len(bLines); i++ {
+		if aLines[i] != bLines[i] {
+			return fmt.Sprintf("  - %s\n  + %s", aLines[i], bLines[i])
+		}
 	}
-
-	return nil
+	return fmt.Sprintf("  definitions differ in length (%d vs %d lines)", len(aLines), len(bLines))
 }
 
 func (s *SchemaProcessor) toCamel(str string) string {
@@ -597,6 +812,33 @@ func setRoot(root, schema *Schema) {
 	for _, one := range schema.OneOf {
 		setRoot(root, one)
 	}
+	for _, one := range schema.AllOf {
+		setRoot(root, one)
+	}
+	for _, one := range schema.AnyOf {
+		setRoot(root, one)
+	}
+
+	for name, def := range schema.Definitions {
+		setRoot(root, def)
+		if def.Name() == "" {
+			def.Title = name
+		}
+	}
+	for name, def := range schema.Defs {
+		setRoot(root, def)
+		if def.Name() == "" {
+			def.Title = name
+		}
+	}
+
+	if schema.Ref != "" && isNamedDefRef(schema.Ref) {
+		// Named definitions are resolved lazily by processSchema (via
+		// refTypes) so they're generated once and reused by name
+		// instead of being inlined at every use site. Root pointers
+		// on the definitions themselves are already set above.
+		return
+	}
 
 	if schema.Ref != "" {
 		schemaPath := strings.Split(schema.Ref, "/")
@@ -0,0 +1,675 @@
+package slipscheme
+
+import (
+	"go/format"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// newTestProcessor builds a SchemaProcessor for unit tests, supplying an
+// empty Replacements map so NewSchemaProcessor's non-negotiable defaults
+// have somewhere to go without requiring every test to opt in.
+func newTestProcessor(opts ...SchemaProcessorOption) *SchemaProcessor {
+	opts = append([]SchemaProcessorOption{PackageName("test"), Replacements(map[string]string{})}, opts...)
+	return NewSchemaProcessor(opts...)
+}
+
+func TestOutputRegistryPutIdenticalReemission(t *testing.T) {
+	r := &outputRegistry{}
+	contents := []byte("package foo\n\ntype Foo struct{}\n")
+
+	if err := r.put("foo.go", "a.json", contents); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	if err := r.put("foo.go", "b.json", contents); err != nil {
+		t.Errorf("identical re-emission from a second file should not conflict: %v", err)
+	}
+}
+
+func TestOutputRegistryPutDivergentConflict(t *testing.T) {
+	r := &outputRegistry{}
+	if err := r.put("foo.go", "a.json", []byte("package foo\n\ntype Foo struct{}\n")); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+
+	err := r.put("foo.go", "b.json", []byte("package foo\n\ntype Foo struct{ X int }\n"))
+	if err == nil {
+		t.Fatal("expected an error for divergent definitions of the same path")
+	}
+	if !strings.Contains(err.Error(), "a.json") || !strings.Contains(err.Error(), "b.json") {
+		t.Errorf("error should name both conflicting sources, got: %v", err)
+	}
+}
+
+func TestOutputRegistryPutConcurrent(t *testing.T) {
+	r := &outputRegistry{}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			path := strings.Repeat("a", i%5+1) + ".go"
+			r.put(path, "concurrent.json", []byte("package foo\n"))
+		}()
+	}
+	wg.Wait()
+
+	if len(r.files) == 0 {
+		t.Fatal("expected concurrent puts to populate the registry")
+	}
+}
+
+func TestEnumValueName(t *testing.T) {
+	tests := []struct {
+		name string
+		v    any
+		want string
+	}{
+		{"positive int", float64(1), "1"},
+		{"negative int", float64(-1), "Neg1"},
+		{"zero", float64(0), "0"},
+		{"string", "active", "active"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := enumValueName(tt.v); got != tt.want {
+				t.Errorf("enumValueName(%v) = %q, want %q", tt.v, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessEnumNegativeValuesDontCollide(t *testing.T) {
+	s := newTestProcessor()
+	schema := &Schema{Title: "Level", Type: INTEGER, Enum: []any{float64(-1), float64(0), float64(1), float64(2)}}
+	setRoot(schema, schema)
+
+	if _, err := s.processEnum(schema, schema.Enum); err != nil {
+		t.Fatalf("processEnum: %v", err)
+	}
+
+	entry, ok := s.registry.files["level.go"]
+	if !ok {
+		t.Fatal("expected level.go to be generated")
+	}
+	contents := string(entry.contents)
+	if !strings.Contains(contents, "LevelNeg1 Level = -1") {
+		t.Errorf("expected a distinct LevelNeg1 constant, got:\n%s", contents)
+	}
+	if !strings.Contains(contents, "Level1 Level = 1") {
+		t.Errorf("expected Level1 to remain untouched, got:\n%s", contents)
+	}
+}
+
+func TestValidationChecksNullableDereferences(t *testing.T) {
+	s := newTestProcessor()
+	min, max := 0.0, 10.0
+	schema := &Schema{Type: NUMBER, Minimum: &min, Maximum: &max, Nullable: true}
+
+	checks, _ := s.validationChecks("Score", "score", schema, false, true)
+	joined := strings.Join(checks, "")
+
+	if strings.Contains(joined, "float64(t.Score)") {
+		t.Errorf("nullable field checks should not operate on the pointer directly, got:\n%s", joined)
+	}
+	if !strings.Contains(joined, "if t.Score != nil {") {
+		t.Errorf("nullable field checks should be nil-guarded, got:\n%s", joined)
+	}
+	if !strings.Contains(joined, "float64((*t.Score))") {
+		t.Errorf("nullable field checks should dereference the pointer, got:\n%s", joined)
+	}
+}
+
+func TestValidationChecksNonNullableUnchanged(t *testing.T) {
+	s := newTestProcessor()
+	min := 0.0
+	schema := &Schema{Type: NUMBER, Minimum: &min}
+
+	checks, _ := s.validationChecks("Score", "score", schema, false, false)
+	joined := strings.Join(checks, "")
+
+	if !strings.Contains(joined, "float64(t.Score)") {
+		t.Errorf("non-nullable field checks should reference t.Score directly, got:\n%s", joined)
+	}
+}
+
+func TestValidationChecksUniqueItems(t *testing.T) {
+	s := newTestProcessor()
+	schema := &Schema{Type: ARRAY, UniqueItems: true}
+
+	checks, _ := s.validationChecks("Tags", "tags", schema, false, false)
+	joined := strings.Join(checks, "")
+
+	if !strings.Contains(joined, "items must be unique") {
+		t.Errorf("expected a uniqueItems check to be generated, got:\n%s", joined)
+	}
+}
+
+func TestValidationChecksPatternWithBackslashCompiles(t *testing.T) {
+	s := newTestProcessor()
+	schema := &Schema{Type: STRING, Pattern: `^\d{3}-\d{4}$`}
+
+	checks, _ := s.validationChecks("Phone", "phone", schema, false, false)
+	joined := strings.Join(checks, "")
+
+	src := "package test\nfunc f() error {\n" + joined + "\nreturn nil\n}\n"
+	if _, err := format.Source([]byte(src)); err != nil {
+		t.Fatalf("pattern containing a backslash produced invalid Go source: %v\n%s", err, src)
+	}
+}
+
+func TestNestedValidateCheckPointer(t *testing.T) {
+	check := nestedValidateCheck("Person", "person", "*Person", map[string]bool{"Person": true}, nil, nil)
+	if !strings.Contains(check, "if t.Person != nil {") || !strings.Contains(check, "t.Person.Validate()") {
+		t.Errorf("expected a nil-guarded cascade into Person.Validate(), got:\n%s", check)
+	}
+}
+
+func TestNestedValidateCheckUnvalidatedType(t *testing.T) {
+	if check := nestedValidateCheck("Person", "person", "*Person", map[string]bool{}, nil, nil); check != "" {
+		t.Errorf("expected no cascade for a type with no Validate(), got:\n%s", check)
+	}
+}
+
+func TestNestedValidateCheckSlice(t *testing.T) {
+	check := nestedValidateCheck("People", "people", "[]*Person", map[string]bool{"Person": true}, nil, nil)
+	if !strings.Contains(check, "for i, v := range t.People") || !strings.Contains(check, "v.Validate()") || !strings.Contains(check, "if v == nil") {
+		t.Errorf("expected a nil-guarded loop cascading into each element's Validate(), got:\n%s", check)
+	}
+}
+
+func TestNestedValidateCheckNamedSliceAlias(t *testing.T) {
+	check := nestedValidateCheck("People", "people", "People", map[string]bool{"Person": true}, map[string]string{"People": "*Person"}, nil)
+	if !strings.Contains(check, "for i, v := range t.People") || !strings.Contains(check, "v.Validate()") {
+		t.Errorf("expected a named array alias to resolve through to its element type, got:\n%s", check)
+	}
+}
+
+func TestNestedValidateCheckScalarIgnored(t *testing.T) {
+	if check := nestedValidateCheck("Name", "name", "string", map[string]bool{}, nil, nil); check != "" {
+		t.Errorf("expected no cascade for a scalar field, got:\n%s", check)
+	}
+}
+
+// definitionsRoot builds a minimal root schema with the given
+// #/definitions entries, wiring up Root pointers and default names the
+// way ParseSchema would.
+func definitionsRoot(defs map[string]*Schema) *Schema {
+	root := &Schema{Type: OBJECT, Definitions: defs}
+	setRoot(root, root)
+	return root
+}
+
+func TestProcessSchemaCascadesNestedStructValidate(t *testing.T) {
+	s := newTestProcessor(WithValidation(true))
+	root := definitionsRoot(map[string]*Schema{
+		"Person": {
+			Type:       OBJECT,
+			Properties: map[string]*Schema{"name": {Type: STRING}},
+			Required:   []string{"name"},
+		},
+	})
+	container := &Schema{
+		Title:      "Container",
+		Type:       OBJECT,
+		Properties: map[string]*Schema{"person": {Ref: "#/definitions/Person"}},
+	}
+	setRoot(root, container)
+
+	if _, err := s.processSchema(container); err != nil {
+		t.Fatalf("processSchema: %v", err)
+	}
+
+	entry, ok := s.registry.files["container.go"]
+	if !ok {
+		t.Fatal("expected container.go to be generated")
+	}
+	contents := string(entry.contents)
+	if !strings.Contains(contents, "if t.Person != nil {") || !strings.Contains(contents, "t.Person.Validate()") {
+		t.Errorf("expected Container.Validate() to cascade into its Person field, got:\n%s", contents)
+	}
+}
+
+func TestProcessSchemaCascadesSliceOfStructValidate(t *testing.T) {
+	s := newTestProcessor(WithValidation(true))
+	root := definitionsRoot(map[string]*Schema{
+		"Person": {
+			Type:       OBJECT,
+			Properties: map[string]*Schema{"name": {Type: STRING}},
+			Required:   []string{"name"},
+		},
+	})
+	container := &Schema{
+		Title: "Container",
+		Type:  OBJECT,
+		Properties: map[string]*Schema{
+			"people": {Type: ARRAY, Items: &Schema{Ref: "#/definitions/Person"}},
+		},
+	}
+	setRoot(root, container)
+
+	if _, err := s.processSchema(container); err != nil {
+		t.Fatalf("processSchema: %v", err)
+	}
+
+	entry, ok := s.registry.files["container.go"]
+	if !ok {
+		t.Fatal("expected container.go to be generated")
+	}
+	contents := string(entry.contents)
+	if !strings.Contains(contents, "for i, v := range t.People") || !strings.Contains(contents, "v.Validate()") {
+		t.Errorf("expected Container.Validate() to cascade into each People element, got:\n%s", contents)
+	}
+}
+
+func TestResolveRefReservesPlaceholderForPatternPropertiesSelfReference(t *testing.T) {
+	s := newTestProcessor()
+	root := definitionsRoot(map[string]*Schema{
+		"Tree": {
+			Type:              OBJECT,
+			Title:             "Tree",
+			PatternProperties: map[string]*Schema{".*": {Ref: "#/definitions/Tree"}},
+		},
+	})
+
+	typeExpr, err := s.resolveRef(&Schema{Ref: "#/definitions/Tree", Root: root})
+	if err != nil {
+		t.Fatalf("resolveRef: %v", err)
+	}
+	if !strings.Contains(typeExpr, "Tree") {
+		t.Errorf("expected the resolved type to reference Tree, got %q", typeExpr)
+	}
+}
+
+func TestMergeSchemasResolvesNamedRefMembers(t *testing.T) {
+	s := newTestProcessor()
+	root := definitionsRoot(map[string]*Schema{
+		"Dog": {
+			Type:       OBJECT,
+			Title:      "Dog",
+			Properties: map[string]*Schema{"breed": {Type: STRING}},
+		},
+		"Cat": {
+			Type:       OBJECT,
+			Title:      "Cat",
+			Properties: map[string]*Schema{"lives": {Type: INTEGER}},
+		},
+	})
+	pet := &Schema{
+		Title: "Pet",
+		Type:  ANY,
+		Root:  root,
+		OneOf: []*Schema{
+			{Ref: "#/definitions/Dog"},
+			{Ref: "#/definitions/Cat"},
+		},
+	}
+
+	if _, err := s.processSchema(pet); err != nil {
+		t.Fatalf("processSchema: %v", err)
+	}
+
+	entry, ok := s.registry.files["pet.go"]
+	if !ok {
+		t.Fatal("expected pet.go to be generated")
+	}
+	if contents := string(entry.contents); !strings.Contains(contents, "Dog") || !strings.Contains(contents, "Cat") {
+		t.Errorf("expected Pet to embed its Dog and Cat oneOf variants, got:\n%s", contents)
+	}
+
+	dog, ok := s.registry.files["dog.go"]
+	if !ok {
+		t.Fatal("expected dog.go to be generated")
+	}
+	if !strings.Contains(string(dog.contents), "Breed") {
+		t.Errorf("expected Dog's breed property to survive the oneOf $ref merge, got:\n%s", dog.contents)
+	}
+
+	cat, ok := s.registry.files["cat.go"]
+	if !ok {
+		t.Fatal("expected cat.go to be generated")
+	}
+	if !strings.Contains(string(cat.contents), "Lives") {
+		t.Errorf("expected Cat's lives property to survive the oneOf $ref merge, got:\n%s", cat.contents)
+	}
+}
+
+func TestProcessAllOfCallsEmbeddedValidate(t *testing.T) {
+	s := newTestProcessor(WithValidation(true))
+	root := definitionsRoot(map[string]*Schema{
+		"Base": {
+			Type:       OBJECT,
+			Properties: map[string]*Schema{"id": {Type: STRING}},
+			Required:   []string{"id"},
+		},
+		"Composed": {
+			Type:  OBJECT,
+			AllOf: []*Schema{{Ref: "#/definitions/Base"}},
+		},
+	})
+
+	if _, err := s.processAllOf(root.Definitions["Composed"]); err != nil {
+		t.Fatalf("processAllOf: %v", err)
+	}
+
+	entry, ok := s.registry.files["composed.go"]
+	if !ok {
+		t.Fatal("expected composed.go to be generated")
+	}
+	contents := string(entry.contents)
+	if !strings.Contains(contents, "t.Base.Validate()") {
+		t.Errorf("expected Composed.Validate() to call the embedded Base's Validate(), got:\n%s", contents)
+	}
+}
+
+func TestProcessAllOfWritesMarkdown(t *testing.T) {
+	s := newTestProcessor(Markdown(true))
+	root := definitionsRoot(map[string]*Schema{
+		"Widget":   {Type: OBJECT, Properties: map[string]*Schema{"name": {Type: STRING}}},
+		"Composed": {Type: OBJECT, AllOf: []*Schema{{Ref: "#/definitions/Widget"}}},
+	})
+
+	if _, err := s.processAllOf(root.Definitions["Composed"]); err != nil {
+		t.Fatalf("processAllOf: %v", err)
+	}
+
+	entry, ok := s.registry.files["composed.md"]
+	if !ok {
+		t.Fatal("expected processAllOf to emit a companion composed.md")
+	}
+	if !strings.Contains(string(entry.contents), "Embeds [`Widget`](#widget)") {
+		t.Errorf("expected composed.md to link to the embedded Widget's own section, got:\n%s", entry.contents)
+	}
+}
+
+func TestProcessAnyOfWritesMarkdown(t *testing.T) {
+	s := newTestProcessor(Markdown(true))
+	schema := &Schema{
+		Title: "Pet",
+		AnyOf: []*Schema{
+			{Title: "Dog", Type: OBJECT, Properties: map[string]*Schema{"breed": {Type: STRING}}},
+			{Title: "Cat", Type: OBJECT, Properties: map[string]*Schema{"lives": {Type: INTEGER}}},
+		},
+	}
+	setRoot(schema, schema)
+
+	if _, err := s.processAnyOf(schema); err != nil {
+		t.Fatalf("processAnyOf: %v", err)
+	}
+
+	if _, ok := s.registry.files["pet.md"]; !ok {
+		t.Error("expected processAnyOf to emit a companion pet.md")
+	}
+}
+
+func TestProcessAnyOfWarnsAboutNonExclusivePopulation(t *testing.T) {
+	s := newTestProcessor()
+	schema := &Schema{
+		Title: "Pet",
+		AnyOf: []*Schema{
+			{Title: "Dog", Type: OBJECT, Properties: map[string]*Schema{"breed": {Type: STRING}}},
+			{Title: "Cat", Type: OBJECT, Properties: map[string]*Schema{"lives": {Type: INTEGER}}},
+		},
+	}
+	setRoot(schema, schema)
+
+	if _, err := s.processAnyOf(schema); err != nil {
+		t.Fatalf("processAnyOf: %v", err)
+	}
+
+	entry, ok := s.registry.files["pet.go"]
+	if !ok {
+		t.Fatal("expected pet.go to be generated")
+	}
+	contents := string(entry.contents)
+	if !strings.Contains(contents, "may end up") {
+		t.Errorf("expected a warning comment about non-exclusive variant population, got:\n%s", contents)
+	}
+}
+
+func TestProcessAnyOfGatesOnVariantValidate(t *testing.T) {
+	s := newTestProcessor(WithValidation(true))
+	root := definitionsRoot(map[string]*Schema{
+		"Dog": {Type: OBJECT, Properties: map[string]*Schema{"breed": {Type: STRING}}, Required: []string{"breed"}},
+		"Cat": {Type: OBJECT, Properties: map[string]*Schema{"lives": {Type: INTEGER}}, Required: []string{"lives"}},
+	})
+	schema := &Schema{
+		Title: "Pet",
+		Root:  root,
+		AnyOf: []*Schema{
+			{Ref: "#/definitions/Dog", Root: root},
+			{Ref: "#/definitions/Cat", Root: root},
+		},
+	}
+
+	if _, err := s.processAnyOf(schema); err != nil {
+		t.Fatalf("processAnyOf: %v", err)
+	}
+
+	entry, ok := s.registry.files["pet.go"]
+	if !ok {
+		t.Fatal("expected pet.go to be generated")
+	}
+	contents := string(entry.contents)
+	if !strings.Contains(contents, "dog.Validate(); err == nil") || !strings.Contains(contents, "cat.Validate(); err == nil") {
+		t.Errorf("expected UnmarshalJSON to gate each variant on its own Validate(), got:\n%s", contents)
+	}
+}
+
+func TestProcessAnyOfGeneratesMarshalJSON(t *testing.T) {
+	s := newTestProcessor()
+	schema := &Schema{
+		Title: "Pet",
+		AnyOf: []*Schema{
+			{Title: "Dog", Type: OBJECT, Properties: map[string]*Schema{"breed": {Type: STRING}}},
+			{Title: "Cat", Type: OBJECT, Properties: map[string]*Schema{"lives": {Type: INTEGER}}},
+		},
+	}
+	setRoot(schema, schema)
+
+	if _, err := s.processAnyOf(schema); err != nil {
+		t.Fatalf("processAnyOf: %v", err)
+	}
+
+	entry, ok := s.registry.files["pet.go"]
+	if !ok {
+		t.Fatal("expected pet.go to be generated")
+	}
+	contents := string(entry.contents)
+	if !strings.Contains(contents, "func (t *Pet) MarshalJSON() ([]byte, error) {") {
+		t.Errorf("expected a generated MarshalJSON, got:\n%s", contents)
+	}
+	if !strings.Contains(contents, "case t.Dog != nil:") || !strings.Contains(contents, "case t.Cat != nil:") {
+		t.Errorf("expected MarshalJSON to re-emit whichever variant is set, got:\n%s", contents)
+	}
+}
+
+func TestProcessDiscriminatorWritesMarkdown(t *testing.T) {
+	s := newTestProcessor(Markdown(true))
+	root := definitionsRoot(map[string]*Schema{
+		"Dog": {Type: OBJECT, Properties: map[string]*Schema{"breed": {Type: STRING}}},
+		"Cat": {Type: OBJECT, Properties: map[string]*Schema{"lives": {Type: INTEGER}}},
+	})
+	schema := &Schema{
+		Title:         "Pet",
+		Root:          root,
+		Discriminator: &Discriminator{PropertyName: "petType"},
+	}
+	variants := []*Schema{
+		{Ref: "#/definitions/Dog", Root: root},
+		{Ref: "#/definitions/Cat", Root: root},
+	}
+
+	if _, err := s.processDiscriminator(schema, variants); err != nil {
+		t.Fatalf("processDiscriminator: %v", err)
+	}
+
+	if _, ok := s.registry.files["pet.md"]; !ok {
+		t.Error("expected processDiscriminator to emit a companion pet.md")
+	}
+}
+
+func TestDetectInputFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want InputFormat
+	}{
+		{"openapi 3.x", `{"openapi":"3.0.0","info":{}}`, OpenAPIInput},
+		{"swagger 2.0", `{"swagger":"2.0","info":{}}`, OpenAPIInput},
+		{"plain json schema", `{"type":"object","properties":{}}`, JSONSchemaInput},
+		{"json schema with an unrelated top-level key", `{"type":"object","$schema":"https://json-schema.org/draft/2020-12/schema"}`, JSONSchemaInput},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectInputFormat([]byte(tt.doc)); got != tt.want {
+				t.Errorf("detectInputFormat(%s) = %v, want %v", tt.doc, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessOpenAPIGeneratesComponentSchemas(t *testing.T) {
+	s := newTestProcessor()
+	doc := `{
+		"openapi": "3.0.0",
+		"components": {
+			"schemas": {
+				"Pet": {
+					"type": "object",
+					"required": ["name"],
+					"properties": {
+						"name": {"type": "string"},
+						"owner": {"$ref": "#/components/schemas/Owner"}
+					}
+				},
+				"Owner": {
+					"type": "object",
+					"properties": {"email": {"type": "string"}}
+				}
+			}
+		}
+	}`
+
+	if err := s.processOpenAPI([]byte(doc)); err != nil {
+		t.Fatalf("processOpenAPI: %v", err)
+	}
+
+	if _, ok := s.registry.files["pet.go"]; !ok {
+		t.Error("expected pet.go to be generated from components.schemas.Pet")
+	}
+	entry, ok := s.registry.files["pet.go"]
+	if !ok {
+		t.Fatal("expected pet.go to be generated")
+	}
+	if !strings.Contains(string(entry.contents), "Owner *Owner") {
+		t.Errorf("expected Pet.Owner to reference the named Owner type via the rewritten $ref, got:\n%s", entry.contents)
+	}
+	if _, ok := s.registry.files["owner.go"]; !ok {
+		t.Error("expected owner.go to be generated from the #/components/schemas/Owner $ref")
+	}
+}
+
+func TestProcessOpenAPIFallsBackToSwaggerDefinitions(t *testing.T) {
+	s := newTestProcessor()
+	doc := `{
+		"swagger": "2.0",
+		"definitions": {
+			"Pet": {
+				"type": "object",
+				"properties": {"name": {"type": "string"}}
+			}
+		}
+	}`
+
+	if err := s.processOpenAPI([]byte(doc)); err != nil {
+		t.Fatalf("processOpenAPI: %v", err)
+	}
+
+	if _, ok := s.registry.files["pet.go"]; !ok {
+		t.Error("expected pet.go to be generated from a Swagger 2.0 definitions entry")
+	}
+}
+
+func TestProcessOpenAPINoSchemasErrors(t *testing.T) {
+	s := newTestProcessor()
+	if err := s.processOpenAPI([]byte(`{"openapi":"3.0.0","info":{}}`)); err == nil {
+		t.Fatal("expected an error when the document has no components.schemas or definitions")
+	}
+}
+
+func TestProcessOpenAPIHonorsXGoTypeAndXGoName(t *testing.T) {
+	s := newTestProcessor()
+	doc := `{
+		"openapi": "3.0.0",
+		"components": {
+			"schemas": {
+				"Pet": {
+					"type": "object",
+					"properties": {
+						"id": {"type": "string", "x-go-type": "uuid.UUID"},
+						"petName": {"type": "string", "x-go-name": "Name"}
+					}
+				}
+			}
+		}
+	}`
+
+	if err := s.processOpenAPI([]byte(doc)); err != nil {
+		t.Fatalf("processOpenAPI: %v", err)
+	}
+
+	entry, ok := s.registry.files["pet.go"]
+	if !ok {
+		t.Fatal("expected pet.go to be generated")
+	}
+	contents := string(entry.contents)
+	if !strings.Contains(contents, "ID uuid.UUID") {
+		t.Errorf("expected id's type to be overridden by x-go-type, got:\n%s", contents)
+	}
+	if !strings.Contains(contents, "Name string") {
+		t.Errorf("expected petName's field to be renamed by x-go-name, got:\n%s", contents)
+	}
+}
+
+func TestProcessOpenAPIDiscriminatorMappingFallback(t *testing.T) {
+	s := newTestProcessor()
+	doc := `{
+		"openapi": "3.0.0",
+		"components": {
+			"schemas": {
+				"Pet": {
+					"oneOf": [
+						{"$ref": "#/components/schemas/Dog"},
+						{"$ref": "#/components/schemas/Cat"}
+					],
+					"discriminator": {
+						"propertyName": "petType",
+						"mapping": {"dog": "#/components/schemas/Dog"}
+					}
+				},
+				"Dog": {"type": "object", "properties": {"breed": {"type": "string"}}},
+				"Cat": {"type": "object", "properties": {"lives": {"type": "integer"}}}
+			}
+		}
+	}`
+
+	if err := s.processOpenAPI([]byte(doc)); err != nil {
+		t.Fatalf("processOpenAPI: %v", err)
+	}
+
+	entry, ok := s.registry.files["pet.go"]
+	if !ok {
+		t.Fatal("expected pet.go to be generated")
+	}
+	contents := string(entry.contents)
+	if !strings.Contains(contents, `case "dog":`) {
+		t.Errorf("expected Dog's mapped tag \"dog\" to be used, got:\n%s", contents)
+	}
+	if !strings.Contains(contents, `case "Cat":`) {
+		t.Errorf("expected Cat, with no mapping entry, to fall back to its bare type name as the tag, got:\n%s", contents)
+	}
+}
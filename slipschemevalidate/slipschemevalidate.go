@@ -0,0 +1,65 @@
+// Package slipschemevalidate provides the runtime helpers used by the
+// Validate() methods slipscheme generates when WithValidation is enabled:
+// a compiled-pattern cache for the JSON Schema "pattern" keyword, and a
+// handful of "format" checkers.
+package slipschemevalidate
+
+import (
+	"regexp"
+	"sync"
+	"time"
+)
+
+var (
+	patternCacheMu sync.RWMutex
+	patternCache   = map[string]*regexp.Regexp{}
+)
+
+// MatchString reports whether value matches the given regular
+// expression pattern, compiling and caching pattern on first use.
+func MatchString(pattern, value string) (bool, error) {
+	patternCacheMu.RLock()
+	re, ok := patternCache[pattern]
+	patternCacheMu.RUnlock()
+
+	if !ok {
+		var err error
+		re, err = regexp.Compile(pattern)
+		if err != nil {
+			return false, err
+		}
+		patternCacheMu.Lock()
+		patternCache[pattern] = re
+		patternCacheMu.Unlock()
+	}
+
+	return re.MatchString(value), nil
+}
+
+var (
+	emailPattern = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+	uuidPattern  = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	uriPattern   = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*://\S+$`)
+)
+
+// IsEmail reports whether s looks like a valid email address. This is a
+// pragmatic check, not a full RFC 5322 validator.
+func IsEmail(s string) bool {
+	return emailPattern.MatchString(s)
+}
+
+// IsUUID reports whether s is a valid UUID.
+func IsUUID(s string) bool {
+	return uuidPattern.MatchString(s)
+}
+
+// IsURI reports whether s looks like an absolute URI.
+func IsURI(s string) bool {
+	return uriPattern.MatchString(s)
+}
+
+// IsDateTime reports whether s is a valid RFC 3339 date-time.
+func IsDateTime(s string) bool {
+	_, err := time.Parse(time.RFC3339, s)
+	return err == nil
+}
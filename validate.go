@@ -0,0 +1,229 @@
+package slipscheme
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WithValidation enables generation of a Validate() error method on every
+// struct generated for an object schema with properties or an allOf
+// composition, enforcing minLength, maxLength, pattern, format,
+// minimum, maximum, exclusiveMinimum, exclusiveMaximum, multipleOf,
+// minItems, maxItems, uniqueItems, minProperties, maxProperties, and
+// required. The struct that merges a oneOf's variants and the anyOf
+// wrapper struct get no Validate() method. Validate() also cascades into any property whose own type has
+// a Validate() - a nested struct, an allOf-embedded member, or a
+// slice/map of either - so a failure anywhere in the tree surfaces at the
+// root, see processAllOf and nestedValidateCheck. The remaining draft
+// 2020-12 composition keywords - not, if/then/else, and dependencies -
+// are parsed onto Schema but are not yet enforced.
+func WithValidation(validate bool) SchemaProcessorOption {
+	return func(s *SchemaProcessor) {
+		s.validate = validate
+	}
+}
+
+// slipschemeValidatePkg is the import path of the generated helper
+// package used by Validate() methods for pattern caching and format
+// checks.
+const slipschemeValidatePkg = "github.com/rdeusser/slipscheme/slipschemevalidate"
+
+// zeroValue returns the Go literal for the zero value of the type
+// processSchema would generate for schema, used by validationChecks to
+// detect unset required fields.
+func zeroValue(schema *Schema) string {
+	switch schema.Type {
+	case STRING:
+		return `""`
+	case INTEGER:
+		return "0"
+	case NUMBER:
+		return "0"
+	case BOOLEAN:
+		return "false"
+	default:
+		return "nil"
+	}
+}
+
+// indentCheck adds one level of indentation to every line of check, a
+// Validate() check body, so it nests correctly inside the surrounding
+// nil guard validationChecks wraps nullable fields' checks in.
+func indentCheck(check string) string {
+	lines := strings.Split(strings.TrimSuffix(check, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = "    " + line
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// nestedValidateCheck returns a Validate() check that cascades into
+// fieldName's own value(s) when its field type - typeExpr, the Go type
+// expression already emitted for it - is itself Validate()-able: a
+// pointer to a generated struct, or a slice/map of one (named array/map
+// alias types are resolved through arrayElemTypes/mapValueTypes first).
+// Without this, validationChecks only enforces the field's own
+// validation keywords, so a nested struct's required-field or constraint
+// failures would be silently swallowed by the parent's Validate().
+// Returns "" when typeExpr isn't one of these shapes, or the referenced
+// type has no Validate() of its own (validatedTypes only tracks types
+// processSchema and processAllOf actually emitted one for).
+func nestedValidateCheck(fieldName, jsonName, typeExpr string, validatedTypes map[string]bool, arrayElemTypes, mapValueTypes map[string]string) string {
+	if elem, ok := arrayElemTypes[typeExpr]; ok {
+		typeExpr = "[]" + elem
+	} else if val, ok := mapValueTypes[typeExpr]; ok {
+		typeExpr = "map[string]" + val
+	}
+	switch {
+	case strings.HasPrefix(typeExpr, "*"):
+		bare := strings.TrimPrefix(typeExpr, "*")
+		if !validatedTypes[bare] {
+			return ""
+		}
+		return fmt.Sprintf("    if t.%s != nil {\n        if err := t.%s.Validate(); err != nil {\n            return err\n        }\n    }\n", fieldName, fieldName)
+	case strings.HasPrefix(typeExpr, "[]"):
+		elem := strings.TrimPrefix(typeExpr, "[]")
+		bare := strings.TrimPrefix(elem, "*")
+		if !validatedTypes[bare] {
+			return ""
+		}
+		guard := ""
+		if strings.HasPrefix(elem, "*") {
+			guard = "        if v == nil {\n            continue\n        }\n"
+		}
+		return fmt.Sprintf("    for i, v := range t.%s {\n%s        if err := v.Validate(); err != nil {\n            return fmt.Errorf(\"%s[%%d]: %%w\", i, err)\n        }\n    }\n", fieldName, guard, jsonName)
+	case strings.HasPrefix(typeExpr, "map[string]"):
+		elem := strings.TrimPrefix(typeExpr, "map[string]")
+		bare := strings.TrimPrefix(elem, "*")
+		if !validatedTypes[bare] {
+			return ""
+		}
+		guard := ""
+		if strings.HasPrefix(elem, "*") {
+			guard = "        if v == nil {\n            continue\n        }\n"
+		}
+		return fmt.Sprintf("    for k, v := range t.%s {\n%s        if err := v.Validate(); err != nil {\n            return fmt.Errorf(\"%s[%%s]: %%w\", k, err)\n        }\n    }\n", fieldName, guard, jsonName)
+	default:
+		return ""
+	}
+}
+
+// validationChecks returns the Validate() method body lines (and any
+// extra imports they require) that enforce schema's validation keywords
+// against the struct field fieldName. nullable must be true when
+// nullableType wrapped the field's type in a pointer, so the checks
+// dereference it behind a nil guard instead of operating on the pointer
+// itself.
+func (s *SchemaProcessor) validationChecks(fieldName, jsonName string, schema *Schema, required, nullable bool) (checks []string, imports []string) {
+	if nullable {
+		if required {
+			checks = append(checks, fmt.Sprintf("    if t.%s == nil {\n        return fmt.Errorf(\"%s is required\")\n    }\n", fieldName, jsonName))
+		}
+	} else if required {
+		checks = append(checks, fmt.Sprintf("    if t.%s == %s {\n        return fmt.Errorf(\"%s is required\")\n    }\n", fieldName, zeroValue(schema), jsonName))
+	}
+
+	// accessor reads the field's value for the checks below: the field
+	// itself, or - for a nullable field, whose Go type is a pointer, see
+	// nullableType - a dereference. The dereference is only reachable
+	// once wrapped in the nil guard built below, since a required check
+	// above already rejects a required-but-nil field, and an optional
+	// nil field skips value checks entirely.
+	accessor := fmt.Sprintf("t.%s", fieldName)
+	if nullable {
+		accessor = fmt.Sprintf("(*t.%s)", fieldName)
+	}
+
+	var valueChecks []string
+	if schema.MinLength != nil {
+		valueChecks = append(valueChecks, fmt.Sprintf("    if len(%s) < %d {\n        return fmt.Errorf(\"%s: length must be >= %d\")\n    }\n", accessor, *schema.MinLength, jsonName, *schema.MinLength))
+	}
+	if schema.MaxLength != nil {
+		valueChecks = append(valueChecks, fmt.Sprintf("    if len(%s) > %d {\n        return fmt.Errorf(\"%s: length must be <= %d\")\n    }\n", accessor, *schema.MaxLength, jsonName, *schema.MaxLength))
+	}
+	if schema.Pattern != "" {
+		// patternErr is composed as a plain Go value first and spliced in
+		// with a single %q, rather than %q-ing schema.Pattern directly
+		// into the fmt.Errorf(\"...\") literal below - the latter nests an
+		// already-quoted-and-escaped value inside literal quotes that are
+		// also in the template, doubling them up into invalid Go source
+		// for any pattern containing a backslash.
+		patternErr := fmt.Sprintf("%s: must match pattern %s", jsonName, schema.Pattern)
+		if nullable {
+			valueChecks = append(valueChecks, fmt.Sprintf("    if ok, err := slipschemevalidate.MatchString(%q, %s); err != nil {\n        return fmt.Errorf(\"%s: %%w\", err)\n    } else if !ok {\n        return fmt.Errorf(%q)\n    }\n", schema.Pattern, accessor, jsonName, patternErr))
+		} else {
+			valueChecks = append(valueChecks, fmt.Sprintf("    if %s != \"\" {\n        if ok, err := slipschemevalidate.MatchString(%q, %s); err != nil {\n            return fmt.Errorf(\"%s: %%w\", err)\n        } else if !ok {\n            return fmt.Errorf(%q)\n        }\n    }\n", accessor, schema.Pattern, accessor, jsonName, patternErr))
+		}
+		imports = append(imports, slipschemeValidatePkg)
+	}
+	if schema.Format != "" {
+		if fn, ok := formatValidators[schema.Format]; ok {
+			if nullable {
+				valueChecks = append(valueChecks, fmt.Sprintf("    if !slipschemevalidate.%s(%s) {\n        return fmt.Errorf(\"%s: not a valid %s\")\n    }\n", fn, accessor, jsonName, schema.Format))
+			} else {
+				valueChecks = append(valueChecks, fmt.Sprintf("    if %s != \"\" && !slipschemevalidate.%s(%s) {\n        return fmt.Errorf(\"%s: not a valid %s\")\n    }\n", accessor, fn, accessor, jsonName, schema.Format))
+			}
+			imports = append(imports, slipschemeValidatePkg)
+		}
+	}
+	if schema.Minimum != nil {
+		valueChecks = append(valueChecks, fmt.Sprintf("    if float64(%s) < %v {\n        return fmt.Errorf(\"%s: must be >= %v\")\n    }\n", accessor, *schema.Minimum, jsonName, *schema.Minimum))
+	}
+	if schema.Maximum != nil {
+		valueChecks = append(valueChecks, fmt.Sprintf("    if float64(%s) > %v {\n        return fmt.Errorf(\"%s: must be <= %v\")\n    }\n", accessor, *schema.Maximum, jsonName, *schema.Maximum))
+	}
+	if schema.ExclusiveMinimum != nil {
+		valueChecks = append(valueChecks, fmt.Sprintf("    if float64(%s) <= %v {\n        return fmt.Errorf(\"%s: must be > %v\")\n    }\n", accessor, *schema.ExclusiveMinimum, jsonName, *schema.ExclusiveMinimum))
+	}
+	if schema.ExclusiveMaximum != nil {
+		valueChecks = append(valueChecks, fmt.Sprintf("    if float64(%s) >= %v {\n        return fmt.Errorf(\"%s: must be < %v\")\n    }\n", accessor, *schema.ExclusiveMaximum, jsonName, *schema.ExclusiveMaximum))
+	}
+	if schema.MultipleOf != nil {
+		valueChecks = append(valueChecks, fmt.Sprintf("    if math.Mod(float64(%s), %v) != 0 {\n        return fmt.Errorf(\"%s: must be a multiple of %v\")\n    }\n", accessor, *schema.MultipleOf, jsonName, *schema.MultipleOf))
+		imports = append(imports, "math")
+	}
+
+	if nullable {
+		if len(valueChecks) > 0 {
+			checks = append(checks, fmt.Sprintf("    if t.%s != nil {\n", fieldName))
+			for _, c := range valueChecks {
+				checks = append(checks, indentCheck(c))
+			}
+			checks = append(checks, "    }\n")
+		}
+	} else {
+		checks = append(checks, valueChecks...)
+	}
+
+	// minItems/maxItems/minProperties/maxProperties apply to slice/map
+	// typed fields, which nullableType never wraps in a pointer (they're
+	// already nil-able), so these always operate on the field directly.
+	if schema.MinItems != nil {
+		checks = append(checks, fmt.Sprintf("    if len(t.%s) < %d {\n        return fmt.Errorf(\"%s: must have at least %d items\")\n    }\n", fieldName, *schema.MinItems, jsonName, *schema.MinItems))
+	}
+	if schema.MaxItems != nil {
+		checks = append(checks, fmt.Sprintf("    if len(t.%s) > %d {\n        return fmt.Errorf(\"%s: must have at most %d items\")\n    }\n", fieldName, *schema.MaxItems, jsonName, *schema.MaxItems))
+	}
+	if schema.MinProperties != nil {
+		checks = append(checks, fmt.Sprintf("    if len(t.%s) < %d {\n        return fmt.Errorf(\"%s: must have at least %d properties\")\n    }\n", fieldName, *schema.MinProperties, jsonName, *schema.MinProperties))
+	}
+	if schema.MaxProperties != nil {
+		checks = append(checks, fmt.Sprintf("    if len(t.%s) > %d {\n        return fmt.Errorf(\"%s: must have at most %d properties\")\n    }\n", fieldName, *schema.MaxProperties, jsonName, *schema.MaxProperties))
+	}
+	if schema.UniqueItems {
+		checks = append(checks, fmt.Sprintf("    {\n        seen := map[string]bool{}\n        for _, v := range t.%s {\n            key := fmt.Sprintf(\"%%v\", v)\n            if seen[key] {\n                return fmt.Errorf(\"%s: items must be unique\")\n            }\n            seen[key] = true\n        }\n    }\n", fieldName, jsonName))
+	}
+	// not, if/then/else, and dependencies are intentionally not enforced
+	// here, see WithValidation's doc comment for the full scope of what
+	// Validate() checks.
+	return checks, imports
+}
+
+// formatValidators maps a JSON Schema "format" value to the
+// slipschemevalidate function that checks it.
+var formatValidators = map[string]string{
+	"email":     "IsEmail",
+	"uuid":      "IsUUID",
+	"uri":       "IsURI",
+	"date-time": "IsDateTime",
+}